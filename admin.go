@@ -0,0 +1,202 @@
+package slackcnr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// AdminConversationsSearchParameters is the parameters of admin.conversations.search API.
+type AdminConversationsSearchParameters struct {
+	Cursor             string
+	Limit              int
+	TeamIDs            []string
+	SearchChannelTypes []string
+}
+
+// AdminConversationsClient is an optional interface for SlackClient, to enumerate channels across an Enterprise Grid org
+// with admin.conversations.search API. slack-go does not provide this API, AdminAPIClient is an implementation.
+type AdminConversationsClient interface {
+	SlackClient
+	AdminConversationsSearchContext(ctx context.Context, params *AdminConversationsSearchParameters) (channels []slack.Channel, nextCursor string, err error)
+}
+
+// ErrAdminNotSupported is returned when WithAdminEnumeration is set, but the slack client does not implement AdminConversationsClient.
+var ErrAdminNotSupported = errors.New("slack client does not support admin.conversations.search")
+
+// adminSearchMaxLimit is the maximum limit of admin.conversations.search API.
+const adminSearchMaxLimit = 20
+
+// WithAdminEnumeration builds a cross-workspace cache of an Enterprise Grid org with admin.conversations.search API,
+// instead of users.conversations and conversations.list APIs.
+// the slack client must implement AdminConversationsClient.
+//
+// requires a user token of an org admin or owner, with admin.conversations:read scope.
+// the API is available only on Enterprise Grid, and its limit is at most 20, so the batch size is capped to 20.
+// the channels are indexed by name across all workspaces, so same-named channels of different workspaces collide,
+// combine with WithChannelPriority or WithKeyFunc to disambiguate.
+func WithAdminEnumeration(teamIDs ...string) ResolverOption {
+	return func(o *resolverOptions) {
+		o.adminEnumeration = true
+		o.adminTeamIDs = teamIDs
+	}
+}
+
+// refreshAdminConversations fetches the channels of the teams with admin.conversations.search API.
+// no team IDs means across the org.
+func (r *Resolver) refreshAdminConversations(ctx context.Context, source refreshSource, teamIDs []string) ([]slack.Channel, string, error) {
+	client, ok := r.client.(AdminConversationsClient)
+	if !ok {
+		return nil, "", ErrAdminNotSupported
+	}
+	limit := r.opts.batchSize
+	if limit > adminSearchMaxLimit {
+		limit = adminSearchMaxLimit
+	}
+	return r.paginate(ctx, source, func(ctx context.Context, cursor string) ([]slack.Channel, string, error) {
+		return client.AdminConversationsSearchContext(ctx, &AdminConversationsSearchParameters{
+			Cursor:  cursor,
+			Limit:   limit,
+			TeamIDs: teamIDs,
+		})
+	})
+}
+
+// AdminAPIClient wraps a slack client, and implements AdminConversationsClient by calling admin.conversations.search API directly.
+type AdminAPIClient struct {
+	SlackClient
+	token      string
+	apiURL     string
+	httpClient *http.Client
+}
+
+var _ AdminConversationsClient = (*AdminAPIClient)(nil)
+
+// AdminAPIClientOption is an option for NewAdminAPIClient.
+type AdminAPIClientOption func(*AdminAPIClient)
+
+// WithAdminAPIURL sets the slack API URL. default is slack.APIURL.
+func WithAdminAPIURL(apiURL string) AdminAPIClientOption {
+	return func(c *AdminAPIClient) {
+		c.apiURL = apiURL
+	}
+}
+
+// WithAdminHTTPClient sets the http client. default is http.DefaultClient.
+func WithAdminHTTPClient(httpClient *http.Client) AdminAPIClientOption {
+	return func(c *AdminAPIClient) {
+		c.httpClient = httpClient
+	}
+}
+
+// NewAdminAPIClient creates a new admin API client. token must be a user token with admin.conversations:read scope.
+func NewAdminAPIClient(client SlackClient, token string, optFns ...AdminAPIClientOption) *AdminAPIClient {
+	c := &AdminAPIClient{
+		SlackClient: client,
+		token:       token,
+		apiURL:      slack.APIURL,
+		httpClient:  http.DefaultClient,
+	}
+	for _, optFn := range optFns {
+		optFn(c)
+	}
+	return c
+}
+
+type adminConversation struct {
+	ID               string   `json:"id"`
+	Name             string   `json:"name"`
+	Purpose          string   `json:"purpose"`
+	Created          int64    `json:"created"`
+	MemberCount      int      `json:"member_count"`
+	IsPrivate        bool     `json:"is_private"`
+	IsArchived       bool     `json:"is_archived"`
+	IsGeneral        bool     `json:"is_general"`
+	IsExtShared      bool     `json:"is_ext_shared"`
+	IsOrgShared      bool     `json:"is_org_shared"`
+	ConnectedTeamIDs []string `json:"connected_team_ids"`
+	InternalTeamIDs  []string `json:"internal_team_ids"`
+}
+
+type adminConversationsSearchResponse struct {
+	slack.SlackResponse
+	Conversations []adminConversation `json:"conversations"`
+	NextCursor    string              `json:"next_cursor"`
+}
+
+func (c *AdminAPIClient) AdminConversationsSearchContext(ctx context.Context, params *AdminConversationsSearchParameters) ([]slack.Channel, string, error) {
+	values := url.Values{}
+	if params.Cursor != "" {
+		values.Set("cursor", params.Cursor)
+	}
+	if params.Limit > 0 {
+		values.Set("limit", strconv.Itoa(params.Limit))
+	}
+	if len(params.TeamIDs) > 0 {
+		values.Set("team_ids", strings.Join(params.TeamIDs, ","))
+	}
+	if len(params.SearchChannelTypes) > 0 {
+		values.Set("search_channel_types", strings.Join(params.SearchChannelTypes, ","))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL+"admin.conversations.search", strings.NewReader(values.Encode()))
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusTooManyRequests {
+		retryAfter, _ := strconv.ParseInt(resp.Header.Get("Retry-After"), 10, 64)
+		return nil, "", &slack.RateLimitedError{RetryAfter: time.Duration(retryAfter) * time.Second}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("admin.conversations.search: unexpected status %s", resp.Status)
+	}
+	var body adminConversationsSearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, "", fmt.Errorf("admin.conversations.search: decode response: %w", err)
+	}
+	if err := body.Err(); err != nil {
+		return nil, "", err
+	}
+	channels := make([]slack.Channel, 0, len(body.Conversations))
+	for _, conv := range body.Conversations {
+		channels = append(channels, conv.toChannel())
+	}
+	return channels, body.NextCursor, nil
+}
+
+func (conv adminConversation) toChannel() slack.Channel {
+	return slack.Channel{
+		GroupConversation: slack.GroupConversation{
+			Conversation: slack.Conversation{
+				ID:               conv.ID,
+				Created:          slack.JSONTime(conv.Created),
+				IsPrivate:        conv.IsPrivate,
+				IsExtShared:      conv.IsExtShared,
+				IsOrgShared:      conv.IsOrgShared,
+				IsShared:         conv.IsExtShared || conv.IsOrgShared,
+				NumMembers:       conv.MemberCount,
+				ConnectedTeamIDs: conv.ConnectedTeamIDs,
+				InternalTeamIDs:  conv.InternalTeamIDs,
+			},
+			Name:       conv.Name,
+			IsArchived: conv.IsArchived,
+			Purpose:    slack.Purpose{Value: conv.Purpose},
+		},
+		IsChannel: !conv.IsPrivate,
+		IsGeneral: conv.IsGeneral,
+	}
+}
@@ -0,0 +1,76 @@
+package slackcnr_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAdminClient struct {
+	mockSlackClient
+}
+
+func (m *mockAdminClient) AdminConversationsSearchContext(ctx context.Context, params *slackcnr.AdminConversationsSearchParameters) ([]slack.Channel, string, error) {
+	args := m.Called(ctx, params)
+	channels, ok := args.Get(0).([]slack.Channel)
+	if !ok {
+		m.t.Error("failed to cast channels")
+	}
+	return channels, args.String(1), args.Error(2)
+}
+
+func TestResolverRefresh__AdminEnumeration(t *testing.T) {
+	client := &mockAdminClient{mockSlackClient{t: t}}
+	defer client.AssertExpectations(t)
+	client.On("AdminConversationsSearchContext", mock.Anything, &slackcnr.AdminConversationsSearchParameters{
+		Limit:   20,
+		TeamIDs: []string{"T1", "T2"},
+	}).Return([]slack.Channel{newTestChannel("C1", "team1-general")}, "cursor1", nil).Once()
+	client.On("AdminConversationsSearchContext", mock.Anything, &slackcnr.AdminConversationsSearchParameters{
+		Cursor:  "cursor1",
+		Limit:   20,
+		TeamIDs: []string{"T1", "T2"},
+	}).Return([]slack.Channel{newTestChannel("C2", "team2-general")}, "", nil).Once()
+	r, err := slackcnr.NewChecked(client,
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+		slackcnr.WithAdminEnumeration("T1", "T2"),
+	)
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	channel, err := r.Lookup(ctx, "team2-general")
+	require.NoError(t, err)
+	require.Equal(t, "C2", channel.ID)
+	client.AssertNotCalled(t, "GetConversationsForUserContext", mock.Anything, mock.Anything)
+
+	_, err = slackcnr.NewChecked(&mockSlackClient{t: t}, slackcnr.WithAdminEnumeration())
+	require.ErrorIs(t, err, slackcnr.ErrAdminNotSupported)
+}
+
+func TestAdminAPIClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		require.Equal(t, "/api/admin.conversations.search", req.URL.Path)
+		require.Equal(t, "Bearer xoxp-test", req.Header.Get("Authorization"))
+		require.NoError(t, req.ParseForm())
+		require.Equal(t, "20", req.PostForm.Get("limit"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok":true,"conversations":[{"id":"C1","name":"general","is_private":false,"is_ext_shared":true,"created":1700000000}],"next_cursor":"next"}`))
+	}))
+	defer srv.Close()
+	client := slackcnr.NewAdminAPIClient(&mockSlackClient{t: t}, "xoxp-test", slackcnr.WithAdminAPIURL(srv.URL+"/api/"))
+	channels, nextCursor, err := client.AdminConversationsSearchContext(context.Background(), &slackcnr.AdminConversationsSearchParameters{Limit: 20})
+	require.NoError(t, err)
+	require.Equal(t, "next", nextCursor)
+	require.Len(t, channels, 1)
+	require.Equal(t, "C1", channels[0].ID)
+	require.Equal(t, "general", channels[0].Name)
+	require.True(t, channels[0].IsExtShared)
+	require.Equal(t, slackcnr.VisibilityShared, slackcnr.ChannelVisibility(&channels[0]))
+}
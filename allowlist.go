@@ -0,0 +1,71 @@
+package slackcnr
+
+import (
+	"sort"
+
+	"github.com/slack-go/slack"
+)
+
+// WithAllowlist restricts name lookups to the given channel names, such as for a bot that may only post to vetted channels.
+// any other channel is reported as ErrNotFound, even if it exists and is cached, and a lookup of it never triggers a refresh.
+// the names are normalized like the looked up names, and compared by their canonical separators with WithSeparatorInsensitive.
+// the resolved channel must be named in the allowlist too, so a shared prefix or a rename can not escape it.
+// calling it more than once extends the allowlist. lookups by ID are not restricted.
+func WithAllowlist(names ...string) ResolverOption {
+	return func(o *resolverOptions) {
+		if o.allowlist == nil {
+			o.allowlist = make(map[string]struct{}, len(names))
+		}
+		for _, name := range names {
+			o.allowlist[name] = struct{}{}
+		}
+	}
+}
+
+// allowlistKey returns the form of the name compared against the allowlist.
+func (o *resolverOptions) allowlistKey(name string) string {
+	name = o.normalizeName(name)
+	if o.separatorInsensitive {
+		name = canonicalSeparators(name)
+	}
+	return name
+}
+
+// allowedNames returns the allowlist keyed by allowlistKey, or nil without WithAllowlist.
+func (o *resolverOptions) allowedNames() map[string]struct{} {
+	if o.allowlist == nil {
+		return nil
+	}
+	allowed := make(map[string]struct{}, len(o.allowlist))
+	for name := range o.allowlist {
+		allowed[o.allowlistKey(name)] = struct{}{}
+	}
+	return allowed
+}
+
+// allows reports whether the name can be looked up.
+func (r *Resolver) allows(channelName string) bool {
+	if r.allowed == nil {
+		return true
+	}
+	_, ok := r.allowed[r.opts.allowlistKey(channelName)]
+	return ok
+}
+
+// allowsChannel reports whether the resolved channel can be returned.
+func (r *Resolver) allowsChannel(channel *slack.Channel) bool {
+	return channel == nil || r.allows(channel.Name)
+}
+
+// allowlistNames returns the names given to WithAllowlist sorted, or nil without it.
+func (o *resolverOptions) allowlistNames() []string {
+	if o.allowlist == nil {
+		return nil
+	}
+	names := make([]string, 0, len(o.allowlist))
+	for name := range o.allowlist {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
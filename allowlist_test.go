@@ -0,0 +1,74 @@
+package slackcnr_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolverLookup__Allowlist(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C012345678", "alerts"),
+		newTestChannel("C023456789", "general"),
+		newTestChannel("C034567890", "team_dev"),
+	}, "", nil).Once()
+	r, err := slackcnr.NewChecked(client,
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+		slackcnr.WithRefreshOnCacheMiss(),
+		slackcnr.WithSeparatorInsensitive(),
+		slackcnr.WithNameNormalizers(slackcnr.TrimHash, slackcnr.LowerCase),
+		slackcnr.WithAllowlist("#Alerts", "team-dev"),
+	)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	for _, name := range []string{"alerts", "#ALERTS"} {
+		channel, err := r.Lookup(ctx, name)
+		require.NoError(t, err, name)
+		require.Equal(t, "C012345678", channel.ID)
+	}
+	channel, err := r.Lookup(ctx, "team dev")
+	require.NoError(t, err)
+	require.Equal(t, "C034567890", channel.ID)
+
+	_, err = r.Lookup(ctx, "general")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound, "a cached channel not in the allowlist is not found")
+	require.Nil(t, r.LookupBestEffort(ctx, "general"))
+
+	channels, err := r.LookupMany(ctx, []string{"alerts", "general"})
+	require.NoError(t, err)
+	require.Len(t, channels, 1)
+	require.Contains(t, channels, "alerts")
+
+	require.Equal(t, []string{"#Alerts", "team-dev"}, r.Config().Allowlist)
+}
+
+func TestResolverLookup__AllowlistRenamed(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C012345678", "ops"),
+	}, "", nil).Once()
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C012345678", "secret-ops"),
+	}, "", nil).Once()
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+		slackcnr.WithTrackRenames(time.Hour),
+		slackcnr.WithAllowlist("ops"),
+	)
+	ctx := context.Background()
+	require.NoError(t, r.Refresh(ctx))
+	_, err := r.Lookup(ctx, "ops")
+	require.NoError(t, err)
+
+	_, err = r.RefreshAndLookup(ctx, "ops")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound, "a rename can not escape the allowlist")
+}
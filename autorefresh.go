@@ -0,0 +1,136 @@
+package slackcnr
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrAutoRefreshNotConfigured is returned by Start when WithAutoRefresh is not set.
+	ErrAutoRefreshNotConfigured = errors.New("auto refresh interval is not configured")
+	// ErrAutoRefreshStarted is returned by Start when the background refresh is already running.
+	ErrAutoRefreshStarted = errors.New("auto refresh is already started")
+)
+
+// WithAutoRefresh sets the interval of the background refresh run by Start.
+func WithAutoRefresh(interval time.Duration) ResolverOption {
+	return func(o *resolverOptions) {
+		o.autoRefreshInterval = interval
+	}
+}
+
+// WithRefreshTimeout bounds each background refresh cycle run by Start.
+// a cycle exceeding the timeout fails, and the next cycle runs at the next interval.
+func WithRefreshTimeout(d time.Duration) ResolverOption {
+	return func(o *resolverOptions) {
+		o.refreshTimeout = d
+	}
+}
+
+// WithAutoRefreshTicker replaces the ticker driving the background refresh run by Start.
+// newTicker returns the channel delivering the ticks and a function to stop it, like time.Ticker.
+// it is meant for tests to drive refresh cycles deterministically, without waiting for real intervals.
+func WithAutoRefreshTicker(newTicker func(interval time.Duration) (ticks <-chan time.Time, stop func())) ResolverOption {
+	return func(o *resolverOptions) {
+		o.newTicker = newTicker
+	}
+}
+
+func newTimeTicker(interval time.Duration) (<-chan time.Time, func()) {
+	ticker := time.NewTicker(interval)
+	return ticker.C, ticker.Stop
+}
+
+// WithContextDeadlinePropagation makes the background refresh cycles inherit the cancellation and the deadline of the context passed to Start.
+// the background refresh stops when that context is done.
+// by default, the cycles only inherit its values, such as the logger set by WithContextLogger,
+// so that the background refresh runs until Stop even if the context passed to Start is canceled.
+func WithContextDeadlinePropagation() ResolverOption {
+	return func(o *resolverOptions) {
+		o.propagateDeadline = true
+	}
+}
+
+// autoRefresher is the lifecycle of the background refresh.
+type autoRefresher struct {
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Start starts refreshing the cache in the background, at the interval set by WithAutoRefresh.
+// each cycle runs with its own context derived from ctx, bounded by WithRefreshTimeout,
+// and a failed cycle is logged without stopping the background refresh.
+// Stop cancels the in-flight cycle and stops the background refresh.
+func (r *Resolver) Start(ctx context.Context) error {
+	ctx = ensureContext(ctx)
+	if r.opts.autoRefreshInterval <= 0 {
+		return ErrAutoRefreshNotConfigured
+	}
+	r.auto.mu.Lock()
+	defer r.auto.mu.Unlock()
+	if r.auto.done != nil {
+		return ErrAutoRefreshStarted
+	}
+	base := context.WithoutCancel(ctx)
+	if r.opts.propagateDeadline {
+		base = ctx
+	}
+	base, cancel := context.WithCancel(base)
+	done := make(chan struct{})
+	r.auto.cancel = cancel
+	r.auto.done = done
+	go func() {
+		defer close(done)
+		r.autoRefresh(base)
+	}()
+	return nil
+}
+
+// Stop stops the background refresh started by Start, and waits for it to exit.
+// it does nothing if the background refresh is not running.
+func (r *Resolver) Stop() {
+	r.auto.mu.Lock()
+	defer r.auto.mu.Unlock()
+	if r.auto.done == nil {
+		return
+	}
+	r.auto.cancel()
+	<-r.auto.done
+	r.auto.cancel = nil
+	r.auto.done = nil
+}
+
+func (r *Resolver) autoRefresh(base context.Context) {
+	newTicker := r.opts.newTicker
+	if newTicker == nil {
+		newTicker = newTimeTicker
+	}
+	ticks, stop := newTicker(r.opts.autoRefreshInterval)
+	defer stop()
+	for {
+		select {
+		case <-base.Done():
+			return
+		case <-ticks:
+		}
+		r.refreshCycle(base)
+	}
+}
+
+// refreshCycle runs a single background refresh, with a child context of base.
+func (r *Resolver) refreshCycle(base context.Context) {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if r.opts.refreshTimeout > 0 {
+		ctx, cancel = context.WithTimeout(base, r.opts.refreshTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(base)
+	}
+	defer cancel()
+	if err := r.Refresh(ctx); err != nil && base.Err() == nil {
+		r.logger(ctx).WarnContext(ctx, "background refresh failed", "error", err)
+	}
+}
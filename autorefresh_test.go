@@ -0,0 +1,118 @@
+package slackcnr_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolverStart__RefreshTimeoutDoesNotStopLoop(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		<-args.Get(0).(context.Context).Done()
+	}).Return([]slack.Channel{}, "", context.DeadlineExceeded).Once()
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).
+		Return([]slack.Channel{newTestChannel("C012345678", "general")}, "", nil)
+	storage := slackcnr.NewInMemoryStorage(0)
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(storage),
+		slackcnr.WithAutoRefresh(10*time.Millisecond),
+		slackcnr.WithRefreshTimeout(20*time.Millisecond),
+	)
+	require.NoError(t, r.Start(context.Background()))
+	defer r.Stop()
+	require.ErrorIs(t, r.Start(context.Background()), slackcnr.ErrAutoRefreshStarted)
+
+	require.Eventually(t, func() bool {
+		_, err := storage.GetByChannelName(context.Background(), "general")
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond, "the cycle after the timed out one refreshes the cache")
+}
+
+func TestResolverStart__BaseContextCancel(t *testing.T) {
+	cases := []struct {
+		name    string
+		opts    []slackcnr.ResolverOption
+		keepsOn bool
+	}{
+		{name: "decoupled by default", keepsOn: true},
+		{name: "propagated", opts: []slackcnr.ResolverOption{slackcnr.WithContextDeadlinePropagation()}},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			client := &mockSlackClient{t: t}
+			var calls atomic.Int64
+			client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+				calls.Add(1)
+			}).Return([]slack.Channel{}, "", nil)
+			r := slackcnr.New(client, append([]slackcnr.ResolverOption{
+				slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+				slackcnr.WithAutoRefresh(5 * time.Millisecond),
+			}, c.opts...)...)
+			ctx, cancel := context.WithCancel(context.Background())
+			require.NoError(t, r.Start(ctx))
+			defer r.Stop()
+			require.Eventually(t, func() bool { return calls.Load() > 0 }, 5*time.Second, 5*time.Millisecond)
+			cancel()
+			// let an in-flight cycle finish.
+			time.Sleep(20 * time.Millisecond)
+			before := calls.Load()
+			time.Sleep(50 * time.Millisecond)
+			require.Equal(t, c.keepsOn, calls.Load() > before)
+		})
+	}
+}
+
+func TestResolverStop__CancelsInFlightRefresh(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	started := make(chan struct{})
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		close(started)
+		<-args.Get(0).(context.Context).Done()
+	}).Return([]slack.Channel{}, "", context.Canceled).Once()
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+		slackcnr.WithAutoRefresh(5*time.Millisecond),
+	)
+	require.NoError(t, r.Start(context.Background()))
+	<-started
+	r.Stop()
+	r.Stop()
+
+	require.ErrorIs(t, slackcnr.New(client).Start(context.Background()), slackcnr.ErrAutoRefreshNotConfigured)
+}
+
+func TestResolverStart__AutoRefreshTicker(t *testing.T) {
+	const cycles = 5
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	refreshed := make(chan struct{})
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+		refreshed <- struct{}{}
+	}).Return([]slack.Channel{}, "", nil).Times(cycles)
+	ticks := make(chan time.Time)
+	var stopped atomic.Bool
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+		slackcnr.WithAutoRefresh(time.Hour),
+		slackcnr.WithAutoRefreshTicker(func(interval time.Duration) (<-chan time.Time, func()) {
+			require.Equal(t, time.Hour, interval)
+			return ticks, func() { stopped.Store(true) }
+		}),
+	)
+	require.NoError(t, r.Start(context.Background()))
+	for i := 0; i < cycles; i++ {
+		ticks <- time.Now()
+		<-refreshed
+	}
+	r.Stop()
+	require.True(t, stopped.Load())
+	client.AssertNumberOfCalls(t, "GetConversationsForUserContext", cycles)
+}
@@ -0,0 +1,81 @@
+package slackcnr
+
+import (
+	"context"
+	"errors"
+
+	"github.com/slack-go/slack"
+)
+
+// IDStorage is an optional interface for storages that support lookup by channel ID.
+type IDStorage interface {
+	Storage
+	GetByChannelID(ctx context.Context, channelID string) (*slack.Channel, error)
+}
+
+// BackfillStorage is an optional interface for storages that can add channels without resetting the cache expiry.
+type BackfillStorage interface {
+	Storage
+	AddChannels(ctx context.Context, channels []slack.Channel) error
+}
+
+// WithBackfillNameOnIDLookup makes LookupByID store a channel fetched with conversations.info API,
+// so that subsequent lookups by name hit the cache.
+// the backfilled channel does not extend the cache expiry, and is replaced by the next refresh like any other channel.
+// it requires the cache storage to implement BackfillStorage, otherwise the channel is not stored.
+func WithBackfillNameOnIDLookup() ResolverOption {
+	return func(o *resolverOptions) {
+		o.backfillOnIDLookup = true
+	}
+}
+
+// LookupByID finds a channel by ID.
+// it looks up the cache storage if it implements IDStorage,
+// and falls back to conversations.info API if the slack client implements ConversationInfoClient.
+// it returns ErrNotFound if the channel can not be found by either.
+func (r *Resolver) LookupByID(ctx context.Context, channelID string) (*slack.Channel, error) {
+	ctx = ensureContext(ctx)
+	if err := r.prepare(ctx); err != nil {
+		return nil, err
+	}
+	if s, ok := r.readStorage().(IDStorage); ok {
+		channel, err := s.GetByChannelID(ctx, channelID)
+		if err == nil {
+			r.stats.hits.Add(1)
+			return channel, nil
+		}
+		if !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+	}
+	r.stats.misses.Add(1)
+	client, ok := r.client.(ConversationInfoClient)
+	if !ok || r.isPinned() {
+		return nil, ErrNotFound
+	}
+	callCtx, cancel := r.callContext(ctx)
+	defer cancel()
+	channel, err := client.GetConversationInfoContext(callCtx, &slack.GetConversationInfoInput{
+		ChannelID:     channelID,
+		IncludeLocale: r.opts.includeLocale,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if r.opts.backfillOnIDLookup {
+		if err := r.backfill(ctx, *channel); err != nil {
+			return nil, err
+		}
+	}
+	return channel, nil
+}
+
+// backfill stores the channels without extending the cache expiry. it is a no-op without a BackfillStorage.
+func (r *Resolver) backfill(ctx context.Context, channels ...slack.Channel) error {
+	s, ok := r.cacheStorage().(BackfillStorage)
+	if !ok {
+		return nil
+	}
+	channels = r.opts.filterChannels(channels)
+	return s.AddChannels(ctx, r.storedChannels(channels))
+}
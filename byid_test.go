@@ -0,0 +1,76 @@
+package slackcnr_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolverLookupByID__Backfill(t *testing.T) {
+	client := &mockConversationInfoClient{mockSlackClient{t: t}}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C012345678", "cached"),
+	}, "", nil).Once()
+	channel := newTestChannel("C023456789", "backfilled")
+	client.On("GetConversationInfoContext", mock.Anything, &slack.GetConversationInfoInput{
+		ChannelID: "C023456789",
+	}).Return(&channel, nil).Once()
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+		slackcnr.WithBackfillNameOnIDLookup(),
+	)
+	ctx := context.Background()
+
+	cached, err := r.LookupByID(ctx, "C012345678")
+	require.NoError(t, err)
+	require.Equal(t, "cached", cached.Name)
+	fetched, err := r.LookupByID(ctx, "C023456789")
+	require.NoError(t, err)
+	require.Equal(t, "backfilled", fetched.Name)
+
+	resolved, err := r.Lookup(ctx, "backfilled")
+	require.NoError(t, err)
+	require.Equal(t, "C023456789", resolved.ID)
+	client.AssertNumberOfCalls(t, "GetConversationInfoContext", 1)
+	client.AssertNumberOfCalls(t, "GetConversationsForUserContext", 1)
+}
+
+func TestResolverLookupByID__NoBackfill(t *testing.T) {
+	client := &mockConversationInfoClient{mockSlackClient{t: t}}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{}, "", nil).Once()
+	channel := newTestChannel("C023456789", "fetched")
+	client.On("GetConversationInfoContext", mock.Anything, mock.Anything).Return(&channel, nil).Once()
+	r := slackcnr.New(client, slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)))
+	ctx := context.Background()
+
+	fetched, err := r.LookupByID(ctx, "C023456789")
+	require.NoError(t, err)
+	require.Equal(t, "fetched", fetched.Name)
+	_, err = r.Lookup(ctx, "fetched")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound)
+}
+
+func TestResolverLookupByID__NotFound(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{}, "", nil).Once()
+	r := slackcnr.New(client, slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)))
+	_, err := r.LookupByID(context.Background(), "C023456789")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound)
+}
+
+func TestInMemoryStorage__AddChannelsKeepsExpiry(t *testing.T) {
+	storage := slackcnr.NewInMemoryStorage(0)
+	ctx := context.Background()
+	require.NoError(t, storage.AddChannels(ctx, []slack.Channel{newTestChannel("C1", "test")}))
+	require.True(t, storage.NeedRefresh(ctx), "backfill must not make an empty cache look fresh")
+	channel, err := storage.GetByChannelID(ctx, "C1")
+	require.NoError(t, err)
+	require.Equal(t, "test", channel.Name)
+}
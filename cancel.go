@@ -0,0 +1,23 @@
+package slackcnr
+
+import "fmt"
+
+// RefreshCanceledError is returned by a refresh stopped by the cancellation or the deadline of its context,
+// with the pass and the page it was on. errors.Is reports context.Canceled or context.DeadlineExceeded for it,
+// so that a caller can tell a refresh it gave up on from a channel not found.
+type RefreshCanceledError struct {
+	// Source is the refresh pass canceled, such as SourceUserConversations.
+	Source Source
+	// Pages is the number of pages of the pass fetched before the cancellation. 0 if the pass had not started.
+	Pages int
+	// Err is the error of the context.
+	Err error
+}
+
+func (e *RefreshCanceledError) Error() string {
+	return fmt.Sprintf("refresh canceled during %s after %d pages: %v", e.Source, e.Pages, e.Err)
+}
+
+func (e *RefreshCanceledError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,44 @@
+package slackcnr
+
+import (
+	"context"
+	"errors"
+
+	"github.com/slack-go/slack"
+)
+
+// Lookuper finds a channel by name. it is implemented by Resolver and Chain,
+// so that call sites can accept either.
+type Lookuper interface {
+	Lookup(ctx context.Context, channelName string) (*slack.Channel, error)
+}
+
+var (
+	_ Lookuper = (*Resolver)(nil)
+	_ Lookuper = (*Chain)(nil)
+)
+
+// Chain looks up a channel with a primary resolver, and falls back to another one when it is not found,
+// such as to combine a fast resolver of the local workspace with a broader admin resolver in a federated setup.
+type Chain struct {
+	primary  *Resolver
+	fallback *Resolver
+}
+
+// NewChain creates a chain of the primary and the fallback resolvers.
+func NewChain(primary, fallback *Resolver) *Chain {
+	return &Chain{
+		primary:  primary,
+		fallback: fallback,
+	}
+}
+
+// Lookup finds a channel by name with the primary resolver, and with the fallback resolver if it returns ErrNotFound.
+// other errors of the primary resolver are returned without consulting the fallback.
+func (c *Chain) Lookup(ctx context.Context, channelName string) (*slack.Channel, error) {
+	channel, err := c.primary.Lookup(ctx, channelName)
+	if !errors.Is(err, ErrNotFound) {
+		return channel, err
+	}
+	return c.fallback.Lookup(ctx, channelName)
+}
@@ -0,0 +1,48 @@
+package slackcnr_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChainLookup(t *testing.T) {
+	ctx := context.Background()
+	newResolver := func(channels ...slack.Channel) *slackcnr.Resolver {
+		storage := slackcnr.NewInMemoryStorage(0)
+		require.NoError(t, storage.SetChannels(ctx, channels))
+		return slackcnr.New(&mockSlackClient{t: t}, slackcnr.WithCacheStorage(storage))
+	}
+	var chain slackcnr.Lookuper = slackcnr.NewChain(
+		newResolver(newTestChannel("C012345678", "general")),
+		newResolver(
+			newTestChannel("C999999999", "general"),
+			newTestChannel("C023456789", "org-announcements"),
+		),
+	)
+
+	channel, err := chain.Lookup(ctx, "general")
+	require.NoError(t, err)
+	require.Equal(t, "C012345678", channel.ID, "the primary wins")
+	channel, err = chain.Lookup(ctx, "org-announcements")
+	require.NoError(t, err)
+	require.Equal(t, "C023456789", channel.ID)
+	_, err = chain.Lookup(ctx, "unknown")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound)
+}
+
+func TestChainLookup__PrimaryError(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{}, "", errors.New("api error")).Once()
+	fallback := slackcnr.New(&mockSlackClient{t: t}, slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)))
+	chain := slackcnr.NewChain(slackcnr.New(client, slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0))), fallback)
+
+	_, err := chain.Lookup(context.Background(), "general")
+	require.ErrorContains(t, err, "api error", "the fallback is not consulted")
+}
@@ -0,0 +1,81 @@
+package slackcnr
+
+import (
+	"sync"
+
+	"github.com/slack-go/slack"
+)
+
+// WithWriteOnlyChanged writes only the channels changed since the last write to the cache storage.
+// it reduces write amplification of DB-backed storages.
+// SetChannels is still called once per refresh, with an empty slice if nothing changed, so that storages can track the refresh time.
+// channels removed by Invalidate, InvalidateWhere or WithEvictRemoved are written again when a refresh returns them.
+// with WithMaxEntries, all channels are written, since the channels evicted by the storage are not known.
+func WithWriteOnlyChanged() ResolverOption {
+	return func(o *resolverOptions) {
+		o.writeOnlyChanged = true
+	}
+}
+
+// WithChannelEquality sets the equality used by WithWriteOnlyChanged.
+// default compares ID, Name and IsArchived.
+func WithChannelEquality(equal func(a, b *slack.Channel) bool) ResolverOption {
+	return func(o *resolverOptions) {
+		o.channelEqual = equal
+	}
+}
+
+func defaultChannelEqual(a, b *slack.Channel) bool {
+	return a.ID == b.ID && a.Name == b.Name && a.IsArchived == b.IsArchived
+}
+
+type changeDetector struct {
+	mu      sync.Mutex
+	written map[string]slack.Channel
+}
+
+// changed returns the channels that differ from the last written ones.
+func (d *changeDetector) changed(channels []slack.Channel, equal func(a, b *slack.Channel) bool) []slack.Channel {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if equal == nil {
+		equal = defaultChannelEqual
+	}
+	changed := make([]slack.Channel, 0, len(channels))
+	for i := range channels {
+		prev, ok := d.written[channels[i].ID]
+		if ok && equal(&prev, &channels[i]) {
+			continue
+		}
+		changed = append(changed, channels[i])
+	}
+	return changed
+}
+
+// commit records the channels as written.
+func (d *changeDetector) commit(channels []slack.Channel) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.written == nil {
+		d.written = make(map[string]slack.Channel, len(channels))
+	}
+	for _, channel := range channels {
+		d.written[channel.ID] = channel
+	}
+}
+
+// forget forgets the channels deleted from the cache storage, so that the next refresh writes them again.
+func (d *changeDetector) forget(ids []string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, id := range ids {
+		delete(d.written, id)
+	}
+}
+
+// reset forgets the written channels, so that the next refresh writes all channels.
+func (d *changeDetector) reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.written = nil
+}
@@ -0,0 +1,71 @@
+package slackcnr_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolverRefresh__WriteOnlyChanged(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	storage := &mockStorage{t: t}
+	defer storage.AssertExpectations(t)
+
+	renamed := newTestChannel("C023456789", "test2-renamed")
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C012345678", "test"),
+		newTestChannel("C023456789", "test2"),
+	}, "", nil).Twice()
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C012345678", "test"),
+		renamed,
+	}, "", nil).Once()
+	storage.On("SetChannels", mock.Anything, []slack.Channel{
+		newTestChannel("C012345678", "test"),
+		newTestChannel("C023456789", "test2"),
+	}).Return(nil).Once()
+	storage.On("SetChannels", mock.Anything, []slack.Channel{}).Return(nil).Once()
+	storage.On("SetChannels", mock.Anything, []slack.Channel{renamed}).Return(nil).Once()
+
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(storage),
+		slackcnr.WithWriteOnlyChanged(),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	require.NoError(t, r.Refresh(ctx))
+	require.NoError(t, r.Refresh(ctx), "unchanged refresh writes no channels")
+	require.NoError(t, r.Refresh(ctx), "only the renamed channel is written")
+}
+
+func TestResolverRefresh__WriteOnlyChangedAfterInvalidate(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C012345678", "general"),
+		newTestChannel("C023456789", "random"),
+	}, "", nil).Times(3)
+	r := slackcnr.New(client, slackcnr.WithWriteOnlyChanged())
+	ctx := context.Background()
+	require.NoError(t, r.Refresh(ctx))
+
+	n, err := r.InvalidateWhere(ctx, func(channel slack.Channel) bool {
+		return channel.Name == "random"
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	channel, err := r.RefreshAndLookup(ctx, "random")
+	require.NoError(t, err, "the invalidated channel is written again by the refresh")
+	require.Equal(t, "C023456789", channel.ID)
+
+	require.NoError(t, r.Invalidate(ctx, "C012345678"))
+	channel, err = r.RefreshAndLookup(ctx, "general")
+	require.NoError(t, err, "the invalidated channel is written again by the refresh")
+	require.Equal(t, "C012345678", channel.ID)
+}
@@ -0,0 +1,118 @@
+package slackcnr
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/slack-go/slack"
+)
+
+// DeletableStorage is an optional interface for storages that can remove channels.
+type DeletableStorage interface {
+	Storage
+	DeleteChannels(ctx context.Context, ids ...string) error
+}
+
+var _ DeletableStorage = (*InMemoryStorage)(nil)
+
+// WithRefreshCheckpointEvery flushes the channels fetched so far to the cache storage every n pages during a refresh,
+// so that a long refresh of an enormous workspace updates the cache progressively instead of only at the end.
+// the flushes are additive. after all passes complete, the channels no longer returned by Slack are evicted.
+//
+// during a refresh, the cache is temporarily inconsistent: it holds new channels already, and removed or renamed ones still.
+// if the refresh fails, the flushed channels are kept and nothing is evicted.
+// eviction is skipped when a pass is skipped by WithTolerateMissingPublicScope, and with WithIncrementalSync,
+// since the refresh does not see all channels then.
+// it requires the cache storage to implement ListableStorage and DeletableStorage, NewChecked returns an error otherwise.
+func WithRefreshCheckpointEvery(n int) ResolverOption {
+	return func(o *resolverOptions) {
+		o.checkpointEvery = n
+	}
+}
+
+type refreshCheckpoint struct {
+	mu      sync.Mutex
+	every   int
+	pages   int
+	pending []slack.Channel
+	flush   func(ctx context.Context, channels []slack.Channel) error
+}
+
+func (r *Resolver) newRefreshCheckpoint() *refreshCheckpoint {
+	return &refreshCheckpoint{
+		every: r.opts.checkpointEvery,
+		flush: func(ctx context.Context, channels []slack.Channel) error {
+			channels = r.storedChannels(channels)
+			channels = r.opts.filterChannels(channels)
+			// a checkpoint must not make the cache look fresh before the refresh completes.
+			if s, ok := r.cacheStorage().(BackfillStorage); ok {
+				return s.AddChannels(ctx, channels)
+			}
+			return r.writeChannels(ctx, channels)
+		},
+	}
+}
+
+// page records a fetched page, and flushes the pending channels every n pages. it is a no-op on a nil checkpoint.
+func (c *refreshCheckpoint) page(ctx context.Context, channels []slack.Channel) error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pages++
+	c.pending = append(c.pending, channels...)
+	if c.pages%c.every != 0 {
+		return nil
+	}
+	pending := c.pending
+	c.pending = nil
+	return c.flush(ctx, pending)
+}
+
+// evictRemoved deletes the cached channels that are not in the refreshed channels.
+func (r *Resolver) evictRemoved(ctx context.Context, channels []slack.Channel) error {
+	listable, ok := r.cacheStorage().(ListableStorage)
+	if !ok {
+		return nil
+	}
+	deletable, ok := r.cacheStorage().(DeletableStorage)
+	if !ok {
+		return nil
+	}
+	cached, err := listable.List(ctx)
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]struct{}, len(channels))
+	for _, channel := range channels {
+		seen[channel.ID] = struct{}{}
+	}
+	var removed []string
+	for _, channel := range cached {
+		if _, ok := seen[channel.ID]; !ok {
+			removed = append(removed, channel.ID)
+		}
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+	if err := deletable.DeleteChannels(ctx, removed...); err != nil {
+		return err
+	}
+	r.changes.forget(removed)
+	return nil
+}
+
+func (o *resolverOptions) validateCheckpoint() error {
+	if o.checkpointEvery <= 0 {
+		return nil
+	}
+	_, listable := o.cacheStorage.(ListableStorage)
+	_, deletable := o.cacheStorage.(DeletableStorage)
+	if !listable || !deletable {
+		return errors.New("cache storage does not support list and delete, required by WithRefreshCheckpointEvery")
+	}
+	return nil
+}
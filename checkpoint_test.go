@@ -0,0 +1,60 @@
+package slackcnr_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolverRefresh__CheckpointEvery(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	storage := slackcnr.NewInMemoryStorage(0)
+	ctx := context.Background()
+	require.NoError(t, storage.SetChannels(ctx, []slack.Channel{newTestChannel("C0", "removed")}))
+
+	cached := func(name string) bool {
+		_, err := storage.GetByChannelName(ctx, name)
+		return err == nil
+	}
+	client.On("GetConversationsForUserContext", mock.Anything, &slack.GetConversationsForUserParameters{
+		Limit: 1000,
+	}).Return([]slack.Channel{newTestChannel("C1", "page1")}, "cursor1", nil).Once()
+	client.On("GetConversationsForUserContext", mock.Anything, &slack.GetConversationsForUserParameters{
+		Cursor: "cursor1",
+		Limit:  1000,
+	}).Run(func(mock.Arguments) {
+		require.False(t, cached("page1"), "the first page is not flushed until the second page")
+	}).Return([]slack.Channel{newTestChannel("C2", "page2")}, "cursor2", nil).Once()
+	client.On("GetConversationsForUserContext", mock.Anything, &slack.GetConversationsForUserParameters{
+		Cursor: "cursor2",
+		Limit:  1000,
+	}).Run(func(mock.Arguments) {
+		require.True(t, cached("page1"), "the checkpoint flushed the first two pages")
+		require.True(t, cached("page2"))
+		require.True(t, cached("removed"), "removed channels are kept until the refresh completes")
+	}).Return([]slack.Channel{newTestChannel("C3", "page3")}, "", nil).Once()
+
+	r, err := slackcnr.NewChecked(client,
+		slackcnr.WithCacheStorage(storage),
+		slackcnr.WithRefreshCheckpointEvery(2),
+	)
+	require.NoError(t, err)
+	require.NoError(t, r.Refresh(ctx))
+
+	channels, err := storage.List(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"page1", "page2", "page3"}, channelNames(channels))
+}
+
+func TestNewChecked__CheckpointRequiresListAndDelete(t *testing.T) {
+	_, err := slackcnr.NewChecked(&mockSlackClient{t: t},
+		slackcnr.WithCacheStorage(slackcnr.NewLRUStorage(10, 0)),
+		slackcnr.WithRefreshCheckpointEvery(2),
+	)
+	require.Error(t, err)
+}
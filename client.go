@@ -0,0 +1,58 @@
+package slackcnr
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+// WithAPIURL sets the base URL of the Slack API, such as a proxy in front of slack.com for proxied or air-gapped deployments.
+// the resolver does not build the slack client by itself, so it only applies to NewWithToken,
+// and is ignored by New and NewChecked. default is slack.APIURL.
+func WithAPIURL(apiURL string) ResolverOption {
+	return func(o *resolverOptions) {
+		o.apiURL = apiURL
+	}
+}
+
+// NewWithToken creates a new resolver with a slack client for the token, like NewChecked.
+// the client uses the base URL set by WithAPIURL.
+func NewWithToken(token string, optFns ...ResolverOption) (*Resolver, error) {
+	if token == "" {
+		return nil, errors.New("slack token is empty")
+	}
+	opts := defaultOptions()
+	for _, optFn := range optFns {
+		optFn(&opts)
+	}
+	var clientOpts []slack.Option
+	if opts.apiURL != "" {
+		apiURL, err := normalizeAPIURL(opts.apiURL)
+		if err != nil {
+			return nil, err
+		}
+		clientOpts = append(clientOpts, slack.OptionAPIURL(apiURL))
+	}
+	return NewChecked(slack.New(token, clientOpts...), optFns...)
+}
+
+// normalizeAPIURL validates the base URL, and adds the trailing slash slack-go expects.
+func normalizeAPIURL(apiURL string) (string, error) {
+	u, err := url.Parse(apiURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid api url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("invalid api url %q: scheme must be http or https", apiURL)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("invalid api url %q: host is empty", apiURL)
+	}
+	if !strings.HasSuffix(apiURL, "/") {
+		apiURL += "/"
+	}
+	return apiURL, nil
+}
@@ -0,0 +1,44 @@
+package slackcnr_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWithToken__APIURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		require.Equal(t, "/proxy/api/users.conversations", req.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok":true,"channels":[{"id":"C012345678","name":"general"}],"response_metadata":{"next_cursor":""}}`))
+	}))
+	defer srv.Close()
+	r, err := slackcnr.NewWithToken("xoxb-test",
+		slackcnr.WithAPIURL(srv.URL+"/proxy/api"),
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+	)
+	require.NoError(t, err)
+	channel, err := r.Lookup(context.Background(), "general")
+	require.NoError(t, err)
+	require.Equal(t, "C012345678", channel.ID)
+	require.Equal(t, srv.URL+"/proxy/api", r.Config().APIURL)
+}
+
+func TestNewWithToken__InvalidAPIURL(t *testing.T) {
+	cases := []string{
+		"slack.example.com/api/",
+		"ftp://slack.example.com/api/",
+		"https:///api/",
+		"://",
+	}
+	for _, apiURL := range cases {
+		_, err := slackcnr.NewWithToken("xoxb-test", slackcnr.WithAPIURL(apiURL))
+		require.ErrorContains(t, err, "invalid api url", apiURL)
+	}
+	_, err := slackcnr.NewWithToken("")
+	require.Error(t, err)
+}
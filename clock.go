@@ -0,0 +1,12 @@
+package slackcnr
+
+import "time"
+
+// now returns the current time for the expiry of the storages, so that tests can freeze it.
+// storages must use it instead of time.Now and time.Since.
+var now = time.Now
+
+// since is time.Since by now.
+func since(t time.Time) time.Duration {
+	return now().Sub(t)
+}
@@ -0,0 +1,40 @@
+package slackcnr_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorage__NeedRefreshByClock(t *testing.T) {
+	cases := []struct {
+		name    string
+		storage func(expire time.Duration) slackcnr.Storage
+	}{
+		{name: "in-memory", storage: func(expire time.Duration) slackcnr.Storage { return slackcnr.NewInMemoryStorage(expire) }},
+		{name: "lru", storage: func(expire time.Duration) slackcnr.Storage { return slackcnr.NewLRUStorage(10, expire) }},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			current := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			slackcnr.SetNow(t, func() time.Time { return current })
+			ctx := context.Background()
+			storage := c.storage(time.Hour)
+			require.True(t, storage.NeedRefresh(ctx), "never set")
+
+			require.NoError(t, storage.SetChannels(ctx, []slack.Channel{newTestChannel("C012345678", "general")}))
+			require.False(t, storage.NeedRefresh(ctx))
+			current = current.Add(time.Hour)
+			require.False(t, storage.NeedRefresh(ctx), "not expired at exactly the expiry")
+			current = current.Add(time.Second)
+			require.True(t, storage.NeedRefresh(ctx))
+
+			require.NoError(t, storage.SetChannels(ctx, []slack.Channel{newTestChannel("C012345678", "general")}))
+			require.False(t, storage.NeedRefresh(ctx), "a write resets the expiry")
+		})
+	}
+}
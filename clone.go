@@ -0,0 +1,47 @@
+package slackcnr
+
+import "maps"
+
+// Clone creates a new resolver with the same client and a copy of the options, with optFns applied on top,
+// such as for a variant with a different batch size or filter. the options are resolved like New.
+//
+// the clone gets a new default in-memory storage unless WithCacheStorage is passed, so it does not share mutable state with r.
+// stats, renames and the background refresh are not shared nor copied either,
+// and the stats of the clone are published to expvar only if WithExpvar is passed with another name.
+// use CloneSharingStorage for a clone reading the cache storage of r.
+func (r *Resolver) Clone(optFns ...ResolverOption) *Resolver {
+	opts := r.opts.clone()
+	opts.cacheStorage = nil
+	for _, optFn := range optFns {
+		optFn(&opts)
+	}
+	return newResolver(r.client, opts)
+}
+
+// CloneSharingStorage creates a new resolver like Clone, that shares the current cache storage of r,
+// so a refresh by either one is visible to both.
+// storage-side options of the clone, such as WithKeyFunc, WithMaxEntries and WithChannelPriority, are not applied to the shared storage,
+// which stays configured by r. if WithCacheStorage is passed, it behaves like Clone.
+func (r *Resolver) CloneSharingStorage(optFns ...ResolverOption) *Resolver {
+	opts := r.opts.clone()
+	opts.cacheStorage = nil
+	for _, optFn := range optFns {
+		optFn(&opts)
+	}
+	if opts.cacheStorage == nil {
+		opts.cacheStorage = r.cacheStorage()
+		opts.sharedStorage = true
+	}
+	return newResolver(r.client, opts)
+}
+
+// clone returns a copy of the options, that options appending to a slice or a map can modify without affecting o.
+func (o resolverOptions) clone() resolverOptions {
+	o.adminTeamIDs = cloneStrings(o.adminTeamIDs)
+	o.channelTypes = cloneStrings(o.channelTypes)
+	o.nameNormalizers = append([]func(string) string(nil), o.nameNormalizers...)
+	o.allowlist = maps.Clone(o.allowlist)
+	o.sharedStorage = false
+	o.expvarName = ""
+	return o
+}
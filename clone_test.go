@@ -0,0 +1,79 @@
+package slackcnr_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolverClone(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, &slack.GetConversationsForUserParameters{
+		Limit: 200,
+	}).Return([]slack.Channel{
+		newTestChannel("C012345678", "general"),
+		newTestChannel("C023456789", "random"),
+	}, "", nil).Once()
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+		slackcnr.WithAllowlist("general"),
+	)
+	clone := r.CloneSharingStorage(slackcnr.WithBatchSize(200), slackcnr.WithAllowlist("random"))
+	require.Same(t, r.Client(), clone.Client())
+	require.Equal(t, 200, clone.Config().BatchSize)
+	require.Equal(t, 1000, r.Config().BatchSize, "the original keeps its batch size")
+	require.Equal(t, []string{"general", "random"}, clone.Config().Allowlist)
+	require.Equal(t, []string{"general"}, r.Config().Allowlist, "the original allowlist is not extended")
+
+	ctx := context.Background()
+	require.NoError(t, clone.Refresh(ctx))
+	channel, err := r.Lookup(ctx, "general")
+	require.NoError(t, err, "the storage is shared")
+	require.Equal(t, "C012345678", channel.ID)
+	_, err = r.Lookup(ctx, "random")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound)
+	_, err = clone.Lookup(ctx, "random")
+	require.NoError(t, err)
+
+	storage := slackcnr.NewInMemoryStorage(0)
+	require.NoError(t, storage.SetChannels(ctx, []slack.Channel{
+		newTestChannel("C034567890", "other"),
+	}))
+	fresh := r.CloneSharingStorage(slackcnr.WithCacheStorage(storage))
+	_, err = fresh.Lookup(ctx, "general")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound, "the clone has its own storage")
+}
+
+func TestResolverClone__OwnStorage(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C012345678", "general"),
+	}, "", nil).Once()
+	ctx := context.Background()
+	storage := slackcnr.NewInMemoryStorage(0)
+	require.NoError(t, storage.SetChannels(ctx, []slack.Channel{
+		newTestChannel("C023456789", "random"),
+		newTestChannel("C034567890", "alerts"),
+	}))
+	r := slackcnr.New(client, slackcnr.WithCacheStorage(storage))
+
+	clone := r.Clone(slackcnr.WithMaxEntries(1, slackcnr.EvictOldest))
+	channel, err := clone.Lookup(ctx, "general")
+	require.NoError(t, err, "the clone refreshes its own storage")
+	require.Equal(t, "C012345678", channel.ID)
+	_, err = r.Lookup(ctx, "general")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound, "the original storage is not written by the clone")
+
+	shared := r.CloneSharingStorage(slackcnr.WithMaxEntries(1, slackcnr.EvictOldest))
+	_, err = shared.Lookup(ctx, "random")
+	require.NoError(t, err)
+	channels, err := storage.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, channels, 2, "the storage-side options of the clone are not applied to the shared storage")
+}
@@ -0,0 +1,89 @@
+package slackcnr
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/slack-go/slack"
+)
+
+// Codec serializes a channel for storages that persist channels as bytes.
+type Codec interface {
+	Marshal(channel slack.Channel) ([]byte, error)
+	Unmarshal(data []byte) (slack.Channel, error)
+}
+
+// JSONCodec is a Codec using encoding/json. it is the default codec.
+type JSONCodec struct{}
+
+func (JSONCodec) Marshal(channel slack.Channel) ([]byte, error) {
+	return json.Marshal(channel)
+}
+
+func (JSONCodec) Unmarshal(data []byte) (slack.Channel, error) {
+	var channel slack.Channel
+	err := json.Unmarshal(data, &channel)
+	return channel, err
+}
+
+// GobCodec is a Codec using encoding/gob. it is more compact than JSONCodec.
+type GobCodec struct{}
+
+func (GobCodec) Marshal(channel slack.Channel) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(channel); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (GobCodec) Unmarshal(data []byte) (slack.Channel, error) {
+	var channel slack.Channel
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&channel)
+	return channel, err
+}
+
+// encodeChannels encodes channels as a sequence of uvarint length-prefixed records.
+func encodeChannels(codec Codec, channels []slack.Channel) ([]byte, error) {
+	var buf bytes.Buffer
+	lenBuf := make([]byte, binary.MaxVarintLen64)
+	for _, channel := range channels {
+		data, err := codec.Marshal(channel)
+		if err != nil {
+			return nil, fmt.Errorf("marshal channel %s: %w", channel.ID, err)
+		}
+		n := binary.PutUvarint(lenBuf, uint64(len(data)))
+		buf.Write(lenBuf[:n])
+		buf.Write(data)
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeChannels decodes channels encoded by encodeChannels.
+func decodeChannels(codec Codec, data []byte) ([]slack.Channel, error) {
+	r := bytes.NewReader(data)
+	channels := make([]slack.Channel, 0)
+	for r.Len() > 0 {
+		size, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, fmt.Errorf("read record length: %w", err)
+		}
+		if size > uint64(r.Len()) {
+			return nil, errors.New("record length exceeds data")
+		}
+		record := make([]byte, size)
+		if _, err := r.Read(record); err != nil {
+			return nil, fmt.Errorf("read record: %w", err)
+		}
+		channel, err := codec.Unmarshal(record)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshal channel: %w", err)
+		}
+		channels = append(channels, channel)
+	}
+	return channels, nil
+}
@@ -0,0 +1,70 @@
+package slackcnr_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodecRoundTrip(t *testing.T) {
+	channel := newTestChannel("C012345678", "test")
+	channel.IsPrivate = true
+	channel.Created = slack.JSONTime(1700000000)
+	channel.Topic = slack.Topic{Value: "topic", Creator: "U012345678", LastSet: slack.JSONTime(1700000001)}
+	channel.Purpose = slack.Purpose{Value: "purpose", Creator: "U023456789", LastSet: slack.JSONTime(1700000002)}
+	channel.Locale = "ja-JP"
+	codecs := map[string]slackcnr.Codec{
+		"json": slackcnr.JSONCodec{},
+		"gob":  slackcnr.GobCodec{},
+	}
+	for name, codec := range codecs {
+		t.Run(name, func(t *testing.T) {
+			data, err := codec.Marshal(channel)
+			require.NoError(t, err)
+			decoded, err := codec.Unmarshal(data)
+			require.NoError(t, err)
+			require.Equal(t, channel, decoded)
+		})
+	}
+}
+
+func TestS3Storage__GobCodec(t *testing.T) {
+	client := newFakeS3Client(t)
+	ctx := context.Background()
+	storage := slackcnr.NewS3Storage(client, "bucket", "channels.gob", time.Hour, slackcnr.WithS3Codec(slackcnr.GobCodec{}))
+	require.NoError(t, storage.SetChannels(ctx, []slack.Channel{
+		newTestChannel("C012345678", "test"),
+		newTestChannel("C023456789", "test2"),
+	}))
+
+	restored := slackcnr.NewS3Storage(client, "bucket", "channels.gob", time.Hour, slackcnr.WithS3Codec(slackcnr.GobCodec{}))
+	channel, err := restored.GetByChannelName(ctx, "test2")
+	require.NoError(t, err)
+	require.Equal(t, "C023456789", channel.ID)
+}
+
+func TestS3Storage__PreservesTopicPurposeAndLocale(t *testing.T) {
+	channel := newTestChannel("C012345678", "test")
+	channel.Topic = slack.Topic{Value: "topic", Creator: "U012345678", LastSet: slack.JSONTime(1700000001)}
+	channel.Purpose = slack.Purpose{Value: "purpose", Creator: "U023456789", LastSet: slack.JSONTime(1700000002)}
+	channel.Locale = "ja-JP"
+	for name, codec := range map[string]slackcnr.Codec{"json": slackcnr.JSONCodec{}, "gob": slackcnr.GobCodec{}} {
+		t.Run(name, func(t *testing.T) {
+			client := newFakeS3Client(t)
+			ctx := context.Background()
+			storage := slackcnr.NewS3Storage(client, "bucket", "channels", time.Hour, slackcnr.WithS3Codec(codec))
+			require.NoError(t, storage.SetChannels(ctx, []slack.Channel{channel}))
+
+			restored := slackcnr.NewS3Storage(client, "bucket", "channels", time.Hour, slackcnr.WithS3Codec(codec))
+			got, err := restored.GetByChannelName(ctx, "test")
+			require.NoError(t, err)
+			require.Equal(t, channel.Topic, got.Topic)
+			require.Equal(t, channel.Purpose, got.Purpose)
+			require.Equal(t, "ja-JP", got.Locale)
+		})
+	}
+}
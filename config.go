@@ -0,0 +1,116 @@
+package slackcnr
+
+import "time"
+
+// ResolverConfig is a read-only snapshot of the effective options of a Resolver.
+// it is a copy, so modifying it does not affect the resolver.
+type ResolverConfig struct {
+	// UserConversations reports whether the refresh calls users.conversations API.
+	UserConversations bool `json:"user_conversations"`
+	// PublicChannels reports whether the refresh calls conversations.list API.
+	PublicChannels bool `json:"public_channels"`
+	// AdminEnumeration reports whether the refresh calls admin.conversations.search API instead.
+	AdminEnumeration bool     `json:"admin_enumeration"`
+	AdminTeamIDs     []string `json:"admin_team_ids,omitempty"`
+
+	BatchSize                  int           `json:"batch_size"`
+	ExcludeArchived            bool          `json:"exclude_archived"`
+	ArchivedOnly               bool          `json:"archived_only"`
+	RefreshOnCacheMiss         bool          `json:"refresh_on_cache_miss"`
+	MinRefreshDeadline         time.Duration `json:"min_refresh_deadline"`
+	MaxAPICalls                int           `json:"max_api_calls"`
+	MinRefreshInterval         time.Duration `json:"min_refresh_interval"`
+	TrackRenames               bool          `json:"track_renames"`
+	RenameRetention            time.Duration `json:"rename_retention"`
+	UnicodeNormalization       bool          `json:"unicode_normalization"`
+	HealthStaleness            time.Duration `json:"health_staleness"`
+	IncrementalSync            bool          `json:"incremental_sync"`
+	TolerateMissingPublicScope bool          `json:"tolerate_missing_public_scope"`
+	WriteOnlyChanged           bool          `json:"write_only_changed"`
+	ExpvarName                 string        `json:"expvar_name,omitempty"`
+	TokenKind                  TokenKind     `json:"token_kind"`
+	// ChannelTypes is the conversation types passed to users.conversations API. nil means the API default.
+	ChannelTypes               []string      `json:"channel_types,omitempty"`
+	IndexSharedChannelPrefixes bool          `json:"index_shared_channel_prefixes"`
+	MemberOnly                 bool          `json:"member_only"`
+	BackfillNameOnIDLookup     bool          `json:"backfill_name_on_id_lookup"`
+	WorkspaceGuard             bool          `json:"workspace_guard"`
+	PerCallTimeout             time.Duration `json:"per_call_timeout"`
+	RefreshWorkers             int           `json:"refresh_workers"`
+	RefreshCheckpointEvery     int           `json:"refresh_checkpoint_every"`
+	TeamDomain                 string        `json:"team_domain,omitempty"`
+	RequireNonEmptyCache       bool          `json:"require_non_empty_cache"`
+	LookupPolicy               LookupPolicy  `json:"lookup_policy"`
+	AutoRefreshInterval        time.Duration `json:"auto_refresh_interval"`
+	RefreshTimeout             time.Duration `json:"refresh_timeout"`
+	ContextDeadlinePropagation bool          `json:"context_deadline_propagation"`
+	RefreshOnAnyError          bool          `json:"refresh_on_any_error"`
+	IncludeLocale              bool          `json:"include_locale"`
+	APIURL                     string        `json:"api_url,omitempty"`
+	SeparatorInsensitive       bool          `json:"separator_insensitive"`
+	LookupConcurrency          int           `json:"lookup_concurrency"`
+	RecordSource               bool          `json:"record_source"`
+	// Allowlist is the channel names lookups are restricted to, sorted. nil means lookups are not restricted.
+	Allowlist []string `json:"allowlist,omitempty"`
+	// MaxEntries is the maximum number of cached channels. 0 means unbounded.
+	MaxEntries     int            `json:"max_entries"`
+	EvictionPolicy EvictionPolicy `json:"eviction_policy"`
+	AtomicReplace  bool           `json:"atomic_replace"`
+	// SkipPublicIfUserComplete reports whether the public channels pass is skipped when the users.conversations pass fits in a single page.
+	SkipPublicIfUserComplete bool `json:"skip_public_if_user_complete"`
+	MaxChannels              int  `json:"max_channels"`
+}
+
+// Config returns the effective configuration of the resolver.
+// values are the resolved ones, e.g. a batch size clamped by New.
+func (r *Resolver) Config() ResolverConfig {
+	o := r.opts
+	return ResolverConfig{
+		UserConversations:          !o.adminEnumeration && o.useUserConversations(),
+		PublicChannels:             !o.adminEnumeration && o.usePublicChannels(),
+		AdminEnumeration:           o.adminEnumeration,
+		AdminTeamIDs:               cloneStrings(o.adminTeamIDs),
+		BatchSize:                  o.batchSize,
+		ExcludeArchived:            o.excludeArchived,
+		ArchivedOnly:               o.archivedOnly,
+		RefreshOnCacheMiss:         o.refreshOnCacheMiss,
+		MinRefreshDeadline:         o.minRefreshDeadline,
+		MaxAPICalls:                o.maxAPICalls,
+		MinRefreshInterval:         o.minRefreshInterval,
+		TrackRenames:               o.trackRenames,
+		RenameRetention:            o.renameRetention,
+		UnicodeNormalization:       o.unicodeNormalization,
+		HealthStaleness:            o.healthStaleness,
+		IncrementalSync:            o.incrementalSync,
+		TolerateMissingPublicScope: o.tolerateMissingScope,
+		WriteOnlyChanged:           o.writeOnlyChanged,
+		ExpvarName:                 o.expvarName,
+		TokenKind:                  o.tokenKind,
+		ChannelTypes:               o.conversationTypes(),
+		IndexSharedChannelPrefixes: o.indexSharedPrefixes,
+		MemberOnly:                 o.memberOnly,
+		BackfillNameOnIDLookup:     o.backfillOnIDLookup,
+		WorkspaceGuard:             o.workspaceGuard,
+		PerCallTimeout:             o.perCallTimeout,
+		RefreshWorkers:             max(o.refreshWorkers, 1),
+		RefreshCheckpointEvery:     o.checkpointEvery,
+		TeamDomain:                 o.teamDomain,
+		RequireNonEmptyCache:       o.requireNonEmpty,
+		LookupPolicy:               o.lookupPolicy,
+		AutoRefreshInterval:        o.autoRefreshInterval,
+		RefreshTimeout:             o.refreshTimeout,
+		ContextDeadlinePropagation: o.propagateDeadline,
+		RefreshOnAnyError:          o.refreshOnAnyError,
+		IncludeLocale:              o.includeLocale,
+		APIURL:                     o.apiURL,
+		SeparatorInsensitive:       o.separatorInsensitive,
+		LookupConcurrency:          max(o.lookupConcurrency, 1),
+		RecordSource:               o.recordSource,
+		Allowlist:                  o.allowlistNames(),
+		MaxEntries:                 o.maxEntries,
+		EvictionPolicy:             o.evictionPolicy,
+		AtomicReplace:              o.atomicReplace,
+		SkipPublicIfUserComplete:   o.skipPublicIfUserComplete,
+		MaxChannels:                o.maxChannels,
+	}
+}
@@ -0,0 +1,47 @@
+package slackcnr_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolver__Config(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	resolver := slackcnr.New(
+		client,
+		slackcnr.WithSearchPublicChannels(),
+		slackcnr.WithExcludeArchived(),
+		slackcnr.WithBatchSize(5000),
+		slackcnr.WithMinRefreshInterval(time.Minute),
+	)
+	cfg := resolver.Config()
+	require.True(t, cfg.UserConversations)
+	require.True(t, cfg.PublicChannels)
+	require.False(t, cfg.AdminEnumeration)
+	require.True(t, cfg.ExcludeArchived)
+	require.Equal(t, 1000, cfg.BatchSize, "batch size should be the clamped value")
+	require.Equal(t, time.Minute, cfg.MinRefreshInterval)
+}
+
+func TestResolver__ConfigIsCopy(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	resolver := slackcnr.New(client, slackcnr.WithAdminEnumeration("T1", "T2"))
+	cfg := resolver.Config()
+	require.Equal(t, []string{"T1", "T2"}, cfg.AdminTeamIDs)
+	require.False(t, cfg.UserConversations)
+	cfg.AdminTeamIDs[0] = "mutated"
+	cfg.BatchSize = 1
+	require.Equal(t, []string{"T1", "T2"}, resolver.Config().AdminTeamIDs)
+	require.NotEqual(t, 1, resolver.Config().BatchSize)
+}
+
+func TestResolver__Client(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	resolver := slackcnr.New(client)
+	require.Same(t, client, resolver.Client())
+}
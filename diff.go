@@ -0,0 +1,134 @@
+package slackcnr
+
+import (
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/slack-go/slack"
+)
+
+// RenamePair is a channel renamed between two refreshes.
+type RenamePair struct {
+	ChannelID string
+	OldName   string
+	NewName   string
+}
+
+type refreshDiff struct {
+	added   []slack.Channel
+	removed []slack.Channel
+	renamed []RenamePair
+}
+
+type snapshotDiffer struct {
+	mu       sync.Mutex
+	current  map[string]slack.Channel
+	previous map[string]slack.Channel
+	last     *refreshDiff
+	// partial is set when the refresh did not see all channels, such as one cut short by WithMaxChannels.
+	partial bool
+}
+
+// begin starts collecting the channels of a refresh.
+func (d *snapshotDiffer) begin() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.current = make(map[string]slack.Channel)
+	d.partial = false
+}
+
+// markPartial records that the refresh did not see all channels, so that the channels not seen are not reported as removed.
+func (d *snapshotDiffer) markPartial() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.partial = true
+}
+
+func (d *snapshotDiffer) collect(channels []slack.Channel) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.current == nil {
+		return
+	}
+	for _, channel := range channels {
+		d.current[channel.ID] = channel
+	}
+}
+
+// commit finishes the refresh, and computes the diff from the previous one.
+// on a failed refresh, the collected channels are discarded.
+func (d *snapshotDiffer) commit(success bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	current := d.current
+	d.current = nil
+	if !success || current == nil {
+		return
+	}
+	if d.previous != nil {
+		d.last = diffSnapshots(d.previous, current, !d.partial)
+		if d.partial {
+			// the channels not seen are kept, so that the next complete refresh does not report them as added.
+			merged := maps.Clone(d.previous)
+			maps.Copy(merged, current)
+			current = merged
+		}
+	}
+	d.previous = current
+}
+
+// diffSnapshots computes the diff of the snapshots. the removed channels are computed only if complete is true.
+func diffSnapshots(previous, current map[string]slack.Channel, complete bool) *refreshDiff {
+	diff := &refreshDiff{}
+	for id, channel := range current {
+		prev, ok := previous[id]
+		if !ok {
+			diff.added = append(diff.added, channel)
+			continue
+		}
+		if prev.Name != channel.Name {
+			diff.renamed = append(diff.renamed, RenamePair{
+				ChannelID: id,
+				OldName:   prev.Name,
+				NewName:   channel.Name,
+			})
+		}
+	}
+	for id, channel := range previous {
+		if _, ok := current[id]; !ok && complete {
+			diff.removed = append(diff.removed, channel)
+		}
+	}
+	sort.Slice(diff.added, func(i, j int) bool { return diff.added[i].ID < diff.added[j].ID })
+	sort.Slice(diff.removed, func(i, j int) bool { return diff.removed[i].ID < diff.removed[j].ID })
+	sort.Slice(diff.renamed, func(i, j int) bool { return diff.renamed[i].ChannelID < diff.renamed[j].ChannelID })
+	return diff
+}
+
+// LastDiff returns the channels added, removed and renamed between the last two successful refreshes, sorted by ID.
+// ok is false until at least two refreshes have succeeded.
+// a refresh that did not see all channels, such as one cut short by WithMaxChannels, reports no removed channels.
+// with WithIncrementalSync, a refresh only sees the resumed pages, so removed channels are not reliable.
+// the returned slices are copies, that the caller may modify.
+func (r *Resolver) LastDiff() (added, removed []slack.Channel, renamed []RenamePair, ok bool) {
+	r.differ.mu.Lock()
+	defer r.differ.mu.Unlock()
+	if r.differ.last == nil {
+		return nil, nil, nil, false
+	}
+	return cloneChannels(r.differ.last.added), cloneChannels(r.differ.last.removed), slices.Clone(r.differ.last.renamed), true
+}
+
+// cloneChannels returns a deep copy of the channels, nil if empty.
+func cloneChannels(channels []slack.Channel) []slack.Channel {
+	if len(channels) == 0 {
+		return nil
+	}
+	cloned := make([]slack.Channel, len(channels))
+	for i, channel := range channels {
+		cloned[i] = cloneChannel(channel)
+	}
+	return cloned
+}
@@ -0,0 +1,73 @@
+package slackcnr_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolverLastDiff(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C1", "keep"),
+		newTestChannel("C2", "remove"),
+		newTestChannel("C3", "old-name"),
+	}, "", nil).Once()
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C1", "keep"),
+		newTestChannel("C3", "new-name"),
+		newTestChannel("C4", "add"),
+	}, "", nil).Once()
+	r := slackcnr.New(client, slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)))
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	require.NoError(t, r.Refresh(ctx))
+	_, _, _, ok := r.LastDiff()
+	require.False(t, ok)
+
+	require.NoError(t, r.Refresh(ctx))
+	added, removed, renamed, ok := r.LastDiff()
+	require.True(t, ok)
+	require.Equal(t, []slack.Channel{newTestChannel("C4", "add")}, added)
+	require.Equal(t, []slack.Channel{newTestChannel("C2", "remove")}, removed)
+	require.Equal(t, []slackcnr.RenamePair{
+		{ChannelID: "C3", OldName: "old-name", NewName: "new-name"},
+	}, renamed)
+}
+
+func TestResolverLastDiff__TruncatedRefresh(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C1", "keep"),
+		newTestChannel("C2", "not-fetched"),
+	}, "", nil).Once()
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C1", "keep"),
+		newTestChannel("C3", "add"),
+		newTestChannel("C4", "over-the-limit"),
+	}, "", nil).Once()
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+		slackcnr.WithMaxChannels(2),
+	)
+	ctx := context.Background()
+	require.NoError(t, r.Refresh(ctx))
+	require.NoError(t, r.Refresh(ctx))
+
+	added, removed, _, ok := r.LastDiff()
+	require.True(t, ok)
+	require.Equal(t, []slack.Channel{newTestChannel("C3", "add")}, added)
+	require.Empty(t, removed, "the channels a truncated refresh did not fetch are not removed")
+
+	added[0].Name = "modified"
+	added, _, _, _ = r.LastDiff()
+	require.Equal(t, "add", added[0].Name, "the returned slices are copies")
+}
@@ -0,0 +1,67 @@
+package slackcnr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/slack-go/slack"
+)
+
+// DumpIndex writes the name to ID mapping of the cache storage as a human-readable table, for troubleshooting a lookup that fails.
+// with a NameListableStorage, every indexed name is written, including the additional keys such as set by WithKeyFunc,
+// otherwise the names of the channels returned by List.
+// it is read-only, it neither refreshes the cache nor waits for a refresh in flight,
+// nor records the channels as used for EvictLRU.
+func (r *Resolver) DumpIndex(ctx context.Context, w io.Writer) error {
+	ctx = ensureContext(ctx)
+	type entry struct {
+		name    string
+		channel *slack.Channel
+	}
+	var entries []entry
+	switch s := r.readStorage().(type) {
+	case NameListableStorage:
+		names, err := s.ListNames(ctx)
+		if err != nil {
+			return err
+		}
+		get := s.GetByChannelName
+		if peeker, ok := s.(interface {
+			peekByChannelName(channelName string) (*slack.Channel, error)
+		}); ok {
+			get = func(_ context.Context, channelName string) (*slack.Channel, error) {
+				return peeker.peekByChannelName(channelName)
+			}
+		}
+		for _, name := range names {
+			channel, err := get(ctx, name)
+			if errors.Is(err, ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			entries = append(entries, entry{name: name, channel: channel})
+		}
+	case ListableStorage:
+		channels, err := s.List(ctx)
+		if err != nil {
+			return err
+		}
+		for i := range channels {
+			entries = append(entries, entry{name: channels[i].Name, channel: &channels[i]})
+		}
+	default:
+		return ErrListNotSupported
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tID\tARCHIVED\tPRIVATE")
+	for _, e := range entries {
+		fmt.Fprintf(tw, "%s\t%s\t%t\t%t\n", e.name, e.channel.ID, e.channel.IsArchived, e.channel.IsPrivate)
+	}
+	return tw.Flush()
+}
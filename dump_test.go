@@ -0,0 +1,37 @@
+package slackcnr_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolverDumpIndex(t *testing.T) {
+	ctx := context.Background()
+	// an expired cache is dumped as is, without a refresh.
+	storage := slackcnr.NewInMemoryStorage(-1)
+	archived := newTestChannel("C023456789", "old-project")
+	archived.IsArchived = true
+	private := newTestChannel("C034567890", "secret")
+	private.IsPrivate = true
+	require.NoError(t, storage.SetChannels(ctx, []slack.Channel{
+		newTestChannel("C012345678", "general"),
+		archived,
+		private,
+	}))
+	r := slackcnr.New(&mockSlackClient{t: t}, slackcnr.WithCacheStorage(storage))
+
+	var buf bytes.Buffer
+	require.NoError(t, r.DumpIndex(ctx, &buf))
+	require.Equal(t, []string{
+		"NAME         ID          ARCHIVED  PRIVATE",
+		"general      C012345678  false     false",
+		"old-project  C023456789  true      false",
+		"secret       C034567890  false     true",
+	}, strings.Split(strings.TrimSpace(buf.String()), "\n"))
+}
@@ -0,0 +1,100 @@
+package slackcnr
+
+import (
+	"context"
+	"errors"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// EventCacheWarmer keeps the cache of a resolver current from Events API payloads, without full refreshes.
+type EventCacheWarmer struct {
+	r *Resolver
+}
+
+// NewEventCacheWarmer creates an EventCacheWarmer updating the cache of the resolver.
+func NewEventCacheWarmer(r *Resolver) *EventCacheWarmer {
+	return &EventCacheWarmer{r: r}
+}
+
+// HandleEvent applies a channel_created, channel_rename, channel_archive or channel_deleted event to the cache.
+// event is a slackevents.EventsAPIEvent, a slackevents.EventsAPIInnerEvent, or the inner event data, by value or by pointer.
+// other event types are ignored.
+func (w *EventCacheWarmer) HandleEvent(ctx context.Context, event interface{}) error {
+	ctx = ensureContext(ctx)
+	switch ev := event.(type) {
+	case slackevents.EventsAPIEvent:
+		return w.HandleEvent(ctx, ev.InnerEvent.Data)
+	case *slackevents.EventsAPIEvent:
+		return w.HandleEvent(ctx, ev.InnerEvent.Data)
+	case slackevents.EventsAPIInnerEvent:
+		return w.HandleEvent(ctx, ev.Data)
+	case *slackevents.EventsAPIInnerEvent:
+		return w.HandleEvent(ctx, ev.Data)
+	case slackevents.ChannelCreatedEvent:
+		return w.created(ctx, &ev)
+	case *slackevents.ChannelCreatedEvent:
+		return w.created(ctx, ev)
+	case slackevents.ChannelRenameEvent:
+		return w.renamed(ctx, &ev)
+	case *slackevents.ChannelRenameEvent:
+		return w.renamed(ctx, ev)
+	case slackevents.ChannelArchiveEvent:
+		return w.archived(ctx, ev.Channel)
+	case *slackevents.ChannelArchiveEvent:
+		return w.archived(ctx, ev.Channel)
+	case slackevents.ChannelDeletedEvent:
+		return w.r.Invalidate(ctx, ev.Channel)
+	case *slackevents.ChannelDeletedEvent:
+		return w.r.Invalidate(ctx, ev.Channel)
+	}
+	return nil
+}
+
+func (w *EventCacheWarmer) created(ctx context.Context, ev *slackevents.ChannelCreatedEvent) error {
+	var channel slack.Channel
+	channel.ID = ev.Channel.ID
+	channel.Name = ev.Channel.Name
+	channel.IsChannel = ev.Channel.IsChannel
+	channel.Created = slack.JSONTime(ev.Channel.Created)
+	channel.Creator = ev.Channel.Creator
+	return w.r.UpdateChannel(ctx, channel)
+}
+
+func (w *EventCacheWarmer) renamed(ctx context.Context, ev *slackevents.ChannelRenameEvent) error {
+	channel, err := w.cached(ctx, ev.Channel.ID)
+	if err != nil {
+		return err
+	}
+	if channel == nil {
+		channel = &slack.Channel{}
+		channel.ID = ev.Channel.ID
+		channel.Created = slack.JSONTime(ev.Channel.Created)
+	}
+	channel.Name = ev.Channel.Name
+	return w.r.UpdateChannel(ctx, *channel)
+}
+
+func (w *EventCacheWarmer) archived(ctx context.Context, channelID string) error {
+	channel, err := w.cached(ctx, channelID)
+	if err != nil || channel == nil {
+		// an archived channel not cached yet is picked up by the next refresh.
+		return err
+	}
+	channel.IsArchived = true
+	return w.r.UpdateChannel(ctx, *channel)
+}
+
+// cached returns the cached channel with the ID, or nil if it is not cached or the cache storage does not implement IDStorage.
+func (w *EventCacheWarmer) cached(ctx context.Context, channelID string) (*slack.Channel, error) {
+	s, ok := w.r.cacheStorage().(IDStorage)
+	if !ok {
+		return nil, nil
+	}
+	channel, err := s.GetByChannelID(ctx, channelID)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	return channel, err
+}
@@ -0,0 +1,93 @@
+package slackcnr_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/stretchr/testify/require"
+)
+
+func newEventTestResolver(t *testing.T, opts ...slackcnr.ResolverOption) *slackcnr.Resolver {
+	t.Helper()
+	storage := slackcnr.NewInMemoryStorage(0)
+	require.NoError(t, storage.SetChannels(context.Background(), []slack.Channel{
+		newTestChannel("C012345678", "general"),
+		newTestChannel("C023456789", "random"),
+	}))
+	return slackcnr.New(&mockSlackClient{t: t}, append([]slackcnr.ResolverOption{slackcnr.WithCacheStorage(storage)}, opts...)...)
+}
+
+func TestEventCacheWarmer(t *testing.T) {
+	ctx := context.Background()
+	r := newEventTestResolver(t)
+	w := slackcnr.NewEventCacheWarmer(r)
+
+	t.Run("channel_created", func(t *testing.T) {
+		require.NoError(t, w.HandleEvent(ctx, slackevents.EventsAPIEvent{
+			Type: slackevents.CallbackEvent,
+			InnerEvent: slackevents.EventsAPIInnerEvent{
+				Type: "channel_created",
+				Data: &slackevents.ChannelCreatedEvent{
+					Type:    "channel_created",
+					Channel: slackevents.ChannelCreatedInfo{ID: "C034567890", Name: "new-channel", IsChannel: true, Created: 1700000000, Creator: "U012345678"},
+				},
+			},
+		}))
+		channel, err := r.Lookup(ctx, "new-channel")
+		require.NoError(t, err)
+		require.Equal(t, "C034567890", channel.ID)
+		require.Equal(t, "U012345678", channel.Creator)
+	})
+	t.Run("channel_rename", func(t *testing.T) {
+		require.NoError(t, w.HandleEvent(ctx, &slackevents.ChannelRenameEvent{
+			Type:    "channel_rename",
+			Channel: slackevents.ChannelRenameInfo{ID: "C023456789", Name: "random-renamed"},
+		}))
+		_, err := r.Lookup(ctx, "random")
+		require.ErrorIs(t, err, slackcnr.ErrNotFound)
+		channel, err := r.Lookup(ctx, "random-renamed")
+		require.NoError(t, err)
+		require.Equal(t, "C023456789", channel.ID)
+	})
+	t.Run("channel_archive", func(t *testing.T) {
+		require.NoError(t, w.HandleEvent(ctx, slackevents.ChannelArchiveEvent{Type: "channel_archive", Channel: "C012345678"}))
+		channel, err := r.Lookup(ctx, "general")
+		require.NoError(t, err)
+		require.True(t, channel.IsArchived)
+		require.NoError(t, w.HandleEvent(ctx, slackevents.ChannelArchiveEvent{Type: "channel_archive", Channel: "C999999999"}), "an unknown channel is ignored")
+	})
+	t.Run("channel_deleted", func(t *testing.T) {
+		require.NoError(t, w.HandleEvent(ctx, &slackevents.ChannelDeletedEvent{Type: "channel_deleted", Channel: "C034567890"}))
+		_, err := r.Lookup(ctx, "new-channel")
+		require.ErrorIs(t, err, slackcnr.ErrNotFound)
+	})
+	t.Run("unhandled", func(t *testing.T) {
+		require.NoError(t, w.HandleEvent(ctx, &slackevents.AppMentionEvent{Type: "app_mention"}))
+		require.NoError(t, w.HandleEvent(ctx, nil))
+	})
+}
+
+func TestEventCacheWarmer__ArchiveWithExcludeArchived(t *testing.T) {
+	ctx := context.Background()
+	r := newEventTestResolver(t, slackcnr.WithExcludeArchived())
+	require.NoError(t, slackcnr.NewEventCacheWarmer(r).HandleEvent(ctx, &slackevents.ChannelArchiveEvent{Type: "channel_archive", Channel: "C012345678"}))
+	_, err := r.Lookup(ctx, "general")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound)
+}
+
+func TestEventCacheWarmer__RenameWithTrackRenames(t *testing.T) {
+	ctx := context.Background()
+	r := newEventTestResolver(t, slackcnr.WithTrackRenames(time.Hour))
+	require.NoError(t, r.UpdateChannel(ctx, newTestChannel("C012345678", "general")))
+	require.NoError(t, slackcnr.NewEventCacheWarmer(r).HandleEvent(ctx, &slackevents.ChannelRenameEvent{
+		Type:    "channel_rename",
+		Channel: slackevents.ChannelRenameInfo{ID: "C012345678", Name: "general-renamed"},
+	}))
+	channel, err := r.Lookup(ctx, "general")
+	require.NoError(t, err, "the previous name resolves to the renamed channel")
+	require.Equal(t, "general-renamed", channel.Name)
+}
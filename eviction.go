@@ -0,0 +1,141 @@
+package slackcnr
+
+import (
+	"errors"
+	"sort"
+	"sync/atomic"
+)
+
+// EvictionPolicy chooses the channels evicted when the cache storage holds more channels than set by WithMaxEntries.
+type EvictionPolicy int
+
+const (
+	// EvictLRU evicts the least recently looked up channels. channels not looked up yet are evicted first, the one stored first first,
+	// so that a refresh adding channels past the cap does not evict the channels looked up. writing a channel again does not count as a use.
+	EvictLRU EvictionPolicy = iota
+	// EvictOldest evicts the channels stored first.
+	EvictOldest
+)
+
+// String returns the name of the eviction policy.
+func (p EvictionPolicy) String() string {
+	switch p {
+	case EvictLRU:
+		return "lru"
+	case EvictOldest:
+		return "oldest"
+	default:
+		return "unknown"
+	}
+}
+
+// EvictingStorage is an optional interface for storages that can bound the number of cached channels.
+type EvictingStorage interface {
+	Storage
+	// SetMaxEntries makes the storage keep at most n channels, evicting the excess by the policy on each write.
+	// n of 0 disables eviction.
+	SetMaxEntries(n int, policy EvictionPolicy)
+}
+
+var _ EvictingStorage = (*InMemoryStorage)(nil)
+
+// WithMaxEntries bounds the cache storage to n channels for memory-bounded deployments,
+// evicting the excess on each write by the policy. it generalizes LRUStorage as an opt-in of the default storage.
+// an evicted channel is not found until it is written again, so methods relying on the full channel list,
+// such as List, ListNames, Snapshot and WithEvictRemoved, see only the channels kept.
+// combine it with WithRefreshOnCacheMiss to fetch evicted channels again.
+// it requires the cache storage to implement EvictingStorage, NewChecked returns an error otherwise.
+func WithMaxEntries(n int, policy EvictionPolicy) ResolverOption {
+	return func(o *resolverOptions) {
+		o.maxEntries = n
+		o.evictionPolicy = policy
+	}
+}
+
+func (o *resolverOptions) validateMaxEntries() error {
+	if o.maxEntries == 0 {
+		return nil
+	}
+	if o.maxEntries < 0 {
+		return errors.New("max entries must not be negative")
+	}
+	if o.evictionPolicy != EvictLRU && o.evictionPolicy != EvictOldest {
+		return errors.New("unknown eviction policy")
+	}
+	if _, ok := o.cacheStorage.(EvictingStorage); !ok {
+		return errors.New("cache storage does not support max entries")
+	}
+	return nil
+}
+
+// entryAge is the order a channel was stored and last used in, by a counter of the storage.
+// used is 0 until the channel is looked up. it is updated by lookups holding only the read lock, so it is atomic.
+type entryAge struct {
+	stored int64
+	used   atomic.Int64
+}
+
+// SetMaxEntries makes the storage keep at most n channels, and evicts the excess right away.
+func (s *InMemoryStorage) SetMaxEntries(n int, policy EvictionPolicy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.maxEntries = n
+	s.eviction = policy
+	if n <= 0 {
+		s.ages = nil
+		return
+	}
+	if s.ages == nil {
+		s.ages = make(map[string]*entryAge, len(s.channels))
+		ids := make([]string, 0, len(s.channels))
+		for id := range s.channels {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			s.stored(id)
+		}
+	}
+	s.evict()
+}
+
+// stored records the order a channel is stored for the first time in. it is a no-op without max entries.
+func (s *InMemoryStorage) stored(id string) {
+	if s.ages == nil {
+		return
+	}
+	if _, ok := s.ages[id]; ok {
+		return
+	}
+	s.ages[id] = &entryAge{stored: s.seq.Add(1)}
+}
+
+// used records the channel as looked up. it only needs the read lock.
+func (s *InMemoryStorage) used(id string) {
+	if age, ok := s.ages[id]; ok {
+		age.used.Store(s.seq.Add(1))
+	}
+}
+
+// evict deletes the channels in excess of max entries, by the eviction policy.
+func (s *InMemoryStorage) evict() {
+	excess := len(s.channels) - s.maxEntries
+	if s.maxEntries <= 0 || excess <= 0 {
+		return
+	}
+	ids := make([]string, 0, len(s.channels))
+	for id := range s.channels {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := s.ages[ids[i]], s.ages[ids[j]]
+		if s.eviction == EvictLRU {
+			if used, other := a.used.Load(), b.used.Load(); used != other {
+				return used < other
+			}
+		}
+		return a.stored < b.stored
+	})
+	s.deleteChannels(ids[:excess])
+}
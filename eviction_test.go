@@ -0,0 +1,122 @@
+package slackcnr_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryStorage__MaxEntries(t *testing.T) {
+	cases := []struct {
+		policy   slackcnr.EvictionPolicy
+		expected []string
+	}{
+		// general is looked up, so random is the least recently used.
+		{policy: slackcnr.EvictLRU, expected: []string{"alerts", "general"}},
+		{policy: slackcnr.EvictOldest, expected: []string{"alerts", "random"}},
+	}
+	for _, c := range cases {
+		t.Run(c.policy.String(), func(t *testing.T) {
+			ctx := context.Background()
+			storage := slackcnr.NewInMemoryStorage(0)
+			storage.SetMaxEntries(2, c.policy)
+			require.NoError(t, storage.SetChannels(ctx, []slack.Channel{
+				newTestChannel("C012345678", "general"),
+				newTestChannel("C023456789", "random"),
+			}))
+			_, err := storage.GetByChannelName(ctx, "general")
+			require.NoError(t, err)
+			require.NoError(t, storage.AddChannels(ctx, []slack.Channel{
+				newTestChannel("C034567890", "alerts"),
+			}))
+			channels, err := storage.List(ctx)
+			require.NoError(t, err)
+			require.Equal(t, c.expected, channelNames(channels))
+		})
+	}
+}
+
+func TestResolverRefresh__MaxEntries(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C012345678", "general"),
+		newTestChannel("C023456789", "random"),
+		newTestChannel("C034567890", "alerts"),
+	}, "", nil).Once()
+	storage := slackcnr.NewInMemoryStorage(0)
+	r, err := slackcnr.NewChecked(client,
+		slackcnr.WithCacheStorage(storage),
+		slackcnr.WithMaxEntries(2, slackcnr.EvictOldest),
+	)
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, r.Refresh(ctx))
+	size, err := storage.CacheSize(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, size.Channels)
+	_, err = r.Lookup(ctx, "general")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound, "the channel stored first is evicted")
+
+	_, err = slackcnr.NewChecked(client,
+		slackcnr.WithCacheStorage(slackcnr.NewLRUStorage(10, 0)),
+		slackcnr.WithMaxEntries(2, slackcnr.EvictLRU),
+	)
+	require.Error(t, err)
+}
+
+func TestResolverRefresh__MaxEntriesKeepsLookedUpChannels(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C012345678", "general"),
+		newTestChannel("C023456789", "random"),
+		newTestChannel("C034567890", "alerts"),
+	}, "", nil).Twice()
+	storage := slackcnr.NewInMemoryStorage(0)
+	r, err := slackcnr.NewChecked(client,
+		slackcnr.WithCacheStorage(storage),
+		slackcnr.WithMaxEntries(2, slackcnr.EvictLRU),
+	)
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, r.Refresh(ctx))
+	for _, name := range []string{"random", "alerts"} {
+		_, err := r.Lookup(ctx, name)
+		require.NoError(t, err, name)
+	}
+
+	require.NoError(t, r.Refresh(ctx))
+	channels, err := storage.List(ctx)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"random", "alerts"}, channelNames(channels), "the channel added again by the refresh is evicted instead of the looked up ones")
+}
+
+func TestInMemoryStorage__MaxEntriesIgnoresDumpAndForEach(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	ctx := context.Background()
+	storage := slackcnr.NewInMemoryStorage(0)
+	storage.SetMaxEntries(2, slackcnr.EvictLRU)
+	require.NoError(t, storage.SetChannels(ctx, []slack.Channel{
+		newTestChannel("C012345678", "general"),
+		newTestChannel("C023456789", "random"),
+	}))
+	r := slackcnr.New(client, slackcnr.WithCacheStorage(storage))
+	_, err := r.Lookup(ctx, "general")
+	require.NoError(t, err)
+
+	require.NoError(t, r.DumpIndex(ctx, io.Discard))
+	require.NoError(t, r.ForEach(ctx, func(slack.Channel) error { return nil }))
+	require.NoError(t, storage.AddChannels(ctx, []slack.Channel{
+		newTestChannel("C034567890", "alerts"),
+	}))
+	channels, err := storage.List(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"alerts", "general"}, channelNames(channels), "dumping and iterating do not count as lookups")
+}
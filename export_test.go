@@ -0,0 +1,16 @@
+package slackcnr
+
+import (
+	"testing"
+	"time"
+)
+
+// SetNow replaces the clock of the storages until the test ends. tests using it must not run in parallel.
+func SetNow(t testing.TB, fn func() time.Time) {
+	t.Helper()
+	prev := now
+	now = fn
+	t.Cleanup(func() {
+		now = prev
+	})
+}
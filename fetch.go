@@ -0,0 +1,53 @@
+package slackcnr
+
+import (
+	"context"
+	"errors"
+
+	"github.com/slack-go/slack"
+)
+
+// maxFetchScanPages is the number of conversations.list pages LookupOrFetch scans at most on a cache miss.
+const maxFetchScanPages = 10
+
+// LookupOrFetch finds a channel by name like Lookup, but on a cache miss, it scans conversations.list page by page
+// until the channel is found, instead of paginating all channels of the workspace.
+// the scan finds only public channels, and stops after 10 pages with ErrNotFound.
+// the channels of the scanned pages are cached with a BackfillStorage, without extending the cache expiry.
+// with WithRefreshOnCacheMiss, a miss refreshes the cache instead, like Lookup. while pinned by Pin, a miss is not scanned.
+func (r *Resolver) LookupOrFetch(ctx context.Context, channelName string) (*slack.Channel, error) {
+	ctx = ensureContext(ctx)
+	channel, err := r.Lookup(ctx, channelName)
+	if r.opts.refreshOnCacheMiss || !errors.Is(err, ErrNotFound) || r.isPinned() {
+		return channel, err
+	}
+	channelName = r.opts.normalizeName(channelName)
+	if !r.allows(channelName) {
+		return nil, err
+	}
+	return r.scanPublicChannels(ctx, channelName)
+}
+
+// scanPublicChannels fetches public channels until one named channelName is found, backfilling the pages.
+func (r *Resolver) scanPublicChannels(ctx context.Context, channelName string) (*slack.Channel, error) {
+	var cursor string
+	for i := 0; i < maxFetchScanPages; i++ {
+		channels, next, err := r.FetchPage(ctx, SourcePublicChannels, cursor)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.backfill(ctx, channels...); err != nil {
+			return nil, err
+		}
+		for _, channel := range r.opts.filterChannels(r.storedChannels(channels)) {
+			if r.opts.normalizeName(channel.Name) == channelName && r.allowsChannel(&channel) {
+				return &channel, nil
+			}
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	return nil, ErrNotFound
+}
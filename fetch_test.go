@@ -0,0 +1,64 @@
+package slackcnr_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolverLookupOrFetch(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	ctx := context.Background()
+	storage := slackcnr.NewInMemoryStorage(0)
+	require.NoError(t, storage.SetChannels(ctx, []slack.Channel{
+		newTestChannel("C012345678", "general"),
+	}))
+	r := slackcnr.New(client, slackcnr.WithCacheStorage(storage))
+
+	channel, err := r.LookupOrFetch(ctx, "general")
+	require.NoError(t, err, "a cache hit calls no API")
+	require.Equal(t, "C012345678", channel.ID)
+
+	client.On("GetConversationsContext", mock.Anything, mock.MatchedBy(func(params *slack.GetConversationsParameters) bool {
+		return params.Cursor == ""
+	})).Return([]slack.Channel{
+		newTestChannel("C023456789", "random"),
+	}, "next", nil).Once()
+	client.On("GetConversationsContext", mock.Anything, mock.MatchedBy(func(params *slack.GetConversationsParameters) bool {
+		return params.Cursor == "next"
+	})).Return([]slack.Channel{
+		newTestChannel("C034567890", "new-project"),
+		newTestChannel("C045678901", "other"),
+	}, "more", nil).Once()
+	channel, err = r.LookupOrFetch(ctx, "new-project")
+	require.NoError(t, err)
+	require.Equal(t, "C034567890", channel.ID)
+
+	for _, name := range []string{"new-project", "random", "other"} {
+		_, err := r.Lookup(ctx, name)
+		require.NoError(t, err, "%s is cached by the scan", name)
+	}
+	require.False(t, storage.NeedRefresh(ctx))
+}
+
+func TestResolverLookupOrFetch__NotFound(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	ctx := context.Background()
+	storage := slackcnr.NewInMemoryStorage(0)
+	require.NoError(t, storage.SetChannels(ctx, []slack.Channel{
+		newTestChannel("C012345678", "general"),
+	}))
+	client.On("GetConversationsContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C023456789", "random"),
+	}, "next", nil).Times(10)
+	r := slackcnr.New(client, slackcnr.WithCacheStorage(storage))
+
+	_, err := r.LookupOrFetch(ctx, "unknown")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound, "the scan is bounded")
+}
@@ -0,0 +1,60 @@
+package slackcnr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrCacheNotPopulated is returned by Healthy when the resolver has never refreshed successfully.
+	ErrCacheNotPopulated = errors.New("cache has never been populated")
+	// ErrCacheStale is returned by Healthy when the last successful refresh is older than the staleness bound.
+	ErrCacheStale = errors.New("cache is stale")
+)
+
+// WithHealthStaleness sets the staleness bound for Healthy.
+// default is 0, the cache is considered healthy once populated regardless of its age.
+func WithHealthStaleness(d time.Duration) ResolverOption {
+	return func(o *resolverOptions) {
+		o.healthStaleness = d
+	}
+}
+
+type refreshHealth struct {
+	mu      sync.Mutex
+	lastErr error
+}
+
+func (h *refreshHealth) record(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastErr = err
+}
+
+func (h *refreshHealth) err() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.lastErr
+}
+
+// Healthy reports whether the resolver is ready to serve lookups, suitable for readiness probes.
+// it returns nil when the cache has been populated by this resolver within the staleness bound.
+// it never triggers a refresh.
+func (r *Resolver) Healthy(ctx context.Context) error {
+	if err := r.health.err(); err != nil {
+		return fmt.Errorf("last refresh failed: %w", err)
+	}
+	stats := r.Stats()
+	if stats.LastRefreshTime.IsZero() {
+		return ErrCacheNotPopulated
+	}
+	if r.opts.healthStaleness > 0 {
+		if age := time.Since(stats.LastRefreshTime); age > r.opts.healthStaleness {
+			return fmt.Errorf("%w: last refreshed %s ago", ErrCacheStale, age.Truncate(time.Second))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package slackcnr_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolverHealthy(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+		slackcnr.WithHealthStaleness(100*time.Millisecond),
+	)
+	ctx := context.Background()
+	require.ErrorIs(t, r.Healthy(ctx), slackcnr.ErrCacheNotPopulated)
+
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{}, "", nil).Once()
+	require.NoError(t, r.Refresh(ctx))
+	require.NoError(t, r.Healthy(ctx))
+
+	time.Sleep(150 * time.Millisecond)
+	require.ErrorIs(t, r.Healthy(ctx), slackcnr.ErrCacheStale)
+
+	apiErr := errors.New("api error")
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{}, "", apiErr).Once()
+	require.ErrorIs(t, r.Refresh(ctx), apiErr)
+	require.ErrorIs(t, r.Healthy(ctx), apiErr)
+}
@@ -0,0 +1,20 @@
+package slackcnr
+
+import "github.com/slack-go/slack"
+
+// KeyedStorage is an optional interface for storages that index a channel under additional lookup keys.
+// GetByChannelName resolves any of the keys to the single stored channel.
+type KeyedStorage interface {
+	Storage
+	SetKeyFunc(keyFunc func(channel *slack.Channel) []string)
+}
+
+// WithKeyFunc sets the function returning additional lookup keys for a channel, such as custom fields in the topic.
+// the channel name is always a key, and it wins over an additional key of another channel.
+// a channel reachable by several keys is stored once.
+// it is applied to the cache storage if it implements KeyedStorage, NewChecked returns an error otherwise.
+func WithKeyFunc(keyFunc func(channel *slack.Channel) []string) ResolverOption {
+	return func(o *resolverOptions) {
+		o.keyFunc = keyFunc
+	}
+}
@@ -0,0 +1,191 @@
+package slackcnr
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// ListableStorage is an optional interface for storages that can return all cached channels.
+type ListableStorage interface {
+	Storage
+	// List returns all cached channels sorted by name.
+	List(ctx context.Context) ([]slack.Channel, error)
+}
+
+var (
+	_ ListableStorage = (*InMemoryStorage)(nil)
+	_ ListableStorage = (*S3Storage)(nil)
+)
+
+// ErrListNotSupported is returned when the cache storage does not implement ListableStorage.
+var ErrListNotSupported = errors.New("cache storage does not support list")
+
+// List returns all cached channels sorted by name.
+func (r *Resolver) List(ctx context.Context) ([]slack.Channel, error) {
+	ctx = ensureContext(ctx)
+	listable, ok := r.readStorage().(ListableStorage)
+	if !ok {
+		return nil, ErrListNotSupported
+	}
+	if err := r.prepare(ctx); err != nil {
+		return nil, err
+	}
+	started := time.Now()
+	channels, err := listable.List(ctx)
+	r.observeStorage(ctx, "List", started, err)
+	return channels, err
+}
+
+// ListSorted returns all cached channels sorted by less, such as by creation date or member count.
+// channels equal by less keep the name order.
+func (r *Resolver) ListSorted(ctx context.Context, less func(a, b slack.Channel) bool) ([]slack.Channel, error) {
+	channels, err := r.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(channels, func(i, j int) bool {
+		return less(channels[i], channels[j])
+	})
+	return channels, nil
+}
+
+func (s *InMemoryStorage) List(ctx context.Context) ([]slack.Channel, error) {
+	channels := s.all()
+	sort.SliceStable(channels, func(i, j int) bool {
+		return channels[i].Name < channels[j].Name
+	})
+	return channels, nil
+}
+
+func (s *S3Storage) List(ctx context.Context) ([]slack.Channel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(ctx); err != nil {
+		return nil, err
+	}
+	return s.cache.List(ctx)
+}
+
+// RangeStorage is an optional interface for storages that can iterate cached channels without materializing them all.
+type RangeStorage interface {
+	Storage
+	// Range calls fn for each cached channel sorted by name, and stops at the first error fn returns.
+	Range(ctx context.Context, fn func(slack.Channel) error) error
+}
+
+var _ RangeStorage = (*InMemoryStorage)(nil)
+
+// ForEach calls fn for each cached channel sorted by name, and returns the first error fn returns.
+// with a RangeStorage, the channels are streamed without materializing the whole list,
+// otherwise it falls back to List.
+func (r *Resolver) ForEach(ctx context.Context, fn func(slack.Channel) error) error {
+	ctx = ensureContext(ctx)
+	rangeable, ok := r.readStorage().(RangeStorage)
+	if !ok {
+		channels, err := r.List(ctx)
+		if err != nil {
+			return err
+		}
+		for _, channel := range channels {
+			if err := fn(channel); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := r.prepare(ctx); err != nil {
+		return err
+	}
+	return rangeable.Range(ctx, fn)
+}
+
+// Range takes a snapshot of the channel IDs, and calls fn for each channel without holding the lock,
+// so that fn can call the storage. channels removed during the iteration are skipped.
+func (s *InMemoryStorage) Range(ctx context.Context, fn func(slack.Channel) error) error {
+	s.mu.RLock()
+	type entry struct{ id, name string }
+	entries := make([]entry, 0, len(s.channels))
+	for id, channel := range s.channels {
+		entries = append(entries, entry{id: id, name: channel.Name})
+	}
+	s.mu.RUnlock()
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].name < entries[j].name
+	})
+	for _, e := range entries {
+		// iterating is not a lookup, so the channels are not recorded as used for EvictLRU.
+		channel, err := s.getByChannelID(e.id, false)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(*channel); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NameListableStorage is an optional interface for storages that can return the indexed names without the channels.
+type NameListableStorage interface {
+	Storage
+	// ListNames returns all names a channel can be looked up by, sorted.
+	ListNames(ctx context.Context) ([]string, error)
+}
+
+var (
+	_ NameListableStorage = (*InMemoryStorage)(nil)
+	_ NameListableStorage = (*S3Storage)(nil)
+)
+
+// ListNames returns the sorted names channels can be looked up by, such as for an autocomplete index.
+// with a NameListableStorage, it is much cheaper than List since no channel is copied.
+// otherwise it falls back to the channel names returned by List.
+func (r *Resolver) ListNames(ctx context.Context) ([]string, error) {
+	ctx = ensureContext(ctx)
+	s, ok := r.readStorage().(NameListableStorage)
+	if !ok {
+		channels, err := r.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(channels))
+		for _, channel := range channels {
+			names = append(names, channel.Name)
+		}
+		return names, nil
+	}
+	if err := r.prepare(ctx); err != nil {
+		return nil, err
+	}
+	return s.ListNames(ctx)
+}
+
+// ListNames returns the indexed names, including the additional keys set by SetKeyFunc.
+func (s *InMemoryStorage) ListNames(ctx context.Context) ([]string, error) {
+	s.mu.RLock()
+	names := make([]string, 0, len(s.namesById))
+	for name := range s.namesById {
+		names = append(names, name)
+	}
+	s.mu.RUnlock()
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *S3Storage) ListNames(ctx context.Context) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(ctx); err != nil {
+		return nil, err
+	}
+	return s.cache.ListNames(ctx)
+}
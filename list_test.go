@@ -0,0 +1,133 @@
+package slackcnr_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolverList(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	oldest := newTestChannel("C1", "charlie")
+	oldest.Created = slack.JSONTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).Unix())
+	newest := newTestChannel("C2", "alpha")
+	newest.Created = slack.JSONTime(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC).Unix())
+	middle := newTestChannel("C3", "bravo")
+	middle.Created = slack.JSONTime(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC).Unix())
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{oldest, newest, middle}, "", nil).Once()
+	r := slackcnr.New(client, slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)))
+	ctx := context.Background()
+
+	channels, err := r.List(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"alpha", "bravo", "charlie"}, channelNames(channels))
+
+	channels, err = r.ListSorted(ctx, func(a, b slack.Channel) bool {
+		return a.Created < b.Created
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"charlie", "bravo", "alpha"}, channelNames(channels))
+}
+
+func TestResolverList__NotSupported(t *testing.T) {
+	r := slackcnr.New(&mockSlackClient{t: t}, slackcnr.WithCacheStorage(slackcnr.NewLRUStorage(10, 0)))
+	_, err := r.List(context.Background())
+	require.ErrorIs(t, err, slackcnr.ErrListNotSupported)
+}
+
+func TestResolverListNames(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C1", "charlie"),
+		newTestChannel("C2", "alpha"),
+		newTestChannel("C3", "bravo"),
+	}, "", nil).Once()
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+		slackcnr.WithKeyFunc(func(channel *slack.Channel) []string {
+			if channel.ID == "C1" {
+				return []string{"delta"}
+			}
+			return nil
+		}),
+	)
+	names, err := r.ListNames(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"alpha", "bravo", "charlie", "delta"}, names)
+}
+
+func channelNames(channels []slack.Channel) []string {
+	names := make([]string, 0, len(channels))
+	for _, channel := range channels {
+		names = append(names, channel.Name)
+	}
+	return names
+}
+
+func TestResolverForEach(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C1", "charlie"),
+		newTestChannel("C2", "alpha"),
+		newTestChannel("C3", "bravo"),
+	}, "", nil).Once()
+	r := slackcnr.New(client, slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)))
+	ctx := context.Background()
+
+	var names []string
+	require.NoError(t, r.ForEach(ctx, func(channel slack.Channel) error {
+		names = append(names, channel.Name)
+		// fn may call the resolver, the storage lock is not held.
+		_, err := r.Lookup(ctx, channel.Name)
+		return err
+	}))
+	require.Equal(t, []string{"alpha", "bravo", "charlie"}, names)
+
+	errStop := errors.New("stop")
+	names = nil
+	err := r.ForEach(ctx, func(channel slack.Channel) error {
+		names = append(names, channel.Name)
+		if channel.Name == "bravo" {
+			return errStop
+		}
+		return nil
+	})
+	require.ErrorIs(t, err, errStop)
+	require.Equal(t, []string{"alpha", "bravo"}, names)
+}
+
+func TestResolverForEach__FallbackToList(t *testing.T) {
+	ctx := context.Background()
+	backend := slackcnr.NewInMemoryStorage(0)
+	storage := &listOnlyStorage{Storage: backend, list: backend.List}
+	require.NoError(t, storage.SetChannels(ctx, []slack.Channel{
+		newTestChannel("C1", "bravo"),
+		newTestChannel("C2", "alpha"),
+	}))
+	r := slackcnr.New(&mockSlackClient{t: t}, slackcnr.WithCacheStorage(storage))
+	var names []string
+	require.NoError(t, r.ForEach(ctx, func(channel slack.Channel) error {
+		names = append(names, channel.Name)
+		return nil
+	}))
+	require.Equal(t, []string{"alpha", "bravo"}, names)
+}
+
+// listOnlyStorage implements ListableStorage but not RangeStorage.
+type listOnlyStorage struct {
+	slackcnr.Storage
+	list func(ctx context.Context) ([]slack.Channel, error)
+}
+
+func (s *listOnlyStorage) List(ctx context.Context) ([]slack.Channel, error) {
+	return s.list(ctx)
+}
@@ -0,0 +1,58 @@
+package slackcnr
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// WithLogger sets the logger for the resolver. default is slog.Default().
+func WithLogger(logger *slog.Logger) ResolverOption {
+	return func(o *resolverOptions) {
+		o.logger = logger
+	}
+}
+
+type contextLoggerKey struct{}
+
+// WithContextLogger returns a context carrying the logger.
+// the resolver prefers the logger in the context over the one set by WithLogger,
+// so that logs include request-scoped attributes such as request IDs.
+func WithContextLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextLoggerKey{}, logger)
+}
+
+func (r *Resolver) logger(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(contextLoggerKey{}).(*slog.Logger); ok && logger != nil {
+		return logger
+	}
+	if r.opts.logger != nil {
+		return r.opts.logger
+	}
+	return slog.Default()
+}
+
+// maxLoggedNameLength is the maximum number of runes of a channel name written to logs.
+const maxLoggedNameLength = 100
+
+// safeName sanitizes a channel name for logging. it strips control characters and invalid UTF-8,
+// and truncates the name to maxLoggedNameLength runes on a rune boundary.
+// it is only for logs, stored and returned values are never sanitized.
+func safeName(name string) string {
+	var b strings.Builder
+	n := 0
+	for _, r := range name {
+		if r == utf8.RuneError || unicode.IsControl(r) {
+			continue
+		}
+		if n == maxLoggedNameLength {
+			b.WriteString("...")
+			break
+		}
+		b.WriteRune(r)
+		n++
+	}
+	return b.String()
+}
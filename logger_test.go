@@ -0,0 +1,67 @@
+package slackcnr_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolver__ContextLogger(t *testing.T) {
+	var defaultBuf, contextBuf bytes.Buffer
+	opts := &slog.HandlerOptions{Level: slog.LevelDebug}
+	storage := slackcnr.NewInMemoryStorage(0)
+	require.NoError(t, storage.SetChannels(context.Background(), []slack.Channel{}))
+	r := slackcnr.New(&mockSlackClient{t: t},
+		slackcnr.WithCacheStorage(storage),
+		slackcnr.WithLogger(slog.New(slog.NewTextHandler(&defaultBuf, opts))),
+	)
+
+	ctx := slackcnr.WithContextLogger(context.Background(),
+		slog.New(slog.NewTextHandler(&contextBuf, opts)).With("request_id", "req-1"),
+	)
+	_, err := r.Lookup(ctx, "unknown")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound)
+	require.Contains(t, contextBuf.String(), "request_id=req-1")
+	require.Contains(t, contextBuf.String(), "channel_name=unknown")
+	require.Empty(t, defaultBuf.String())
+
+	_, err = r.Lookup(context.Background(), "unknown")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound)
+	require.Contains(t, defaultBuf.String(), "channel_name=unknown")
+}
+
+func TestResolver__LoggedChannelNameIsSanitized(t *testing.T) {
+	var buf bytes.Buffer
+	storage := slackcnr.NewInMemoryStorage(0)
+	require.NoError(t, storage.SetChannels(context.Background(), []slack.Channel{}))
+	r := slackcnr.New(&mockSlackClient{t: t},
+		slackcnr.WithCacheStorage(storage),
+		slackcnr.WithLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))),
+	)
+	cases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "control characters", input: "evil\nname\x1b[31m\t", expected: "evilname[31m"},
+		{name: "long name", input: strings.Repeat("あ", 150), expected: strings.Repeat("あ", 100) + "..."},
+		{name: "invalid utf8", input: "bad\xffname", expected: "badname"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			buf.Reset()
+			_, err := r.Lookup(context.Background(), c.input)
+			require.ErrorIs(t, err, slackcnr.ErrNotFound)
+			var record map[string]any
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+			require.Equal(t, c.expected, record["channel_name"])
+		})
+	}
+}
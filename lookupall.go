@@ -0,0 +1,75 @@
+package slackcnr
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/slack-go/slack"
+)
+
+// MultiNameStorage is an optional interface for storages that keep all channels sharing a name.
+type MultiNameStorage interface {
+	Storage
+	// GetAllByChannelName returns all channels named channelName, the one GetByChannelName returns first.
+	GetAllByChannelName(ctx context.Context, channelName string) ([]slack.Channel, error)
+}
+
+var _ MultiNameStorage = (*InMemoryStorage)(nil)
+
+// ErrLookupAllNotSupported is returned when the cache storage does not implement MultiNameStorage.
+var ErrLookupAllNotSupported = errors.New("cache storage does not support lookup of all channels by name")
+
+// LookupAll finds all channels named channelName, such as same-named channels of different workspaces.
+// the channel Lookup returns comes first. it returns ErrNotFound if no channel matches.
+// like Lookup, channels not in WithAllowlist are not found.
+func (r *Resolver) LookupAll(ctx context.Context, channelName string) ([]slack.Channel, error) {
+	ctx = ensureContext(ctx)
+	channelName = r.opts.normalizeName(channelName)
+	storage, ok := r.readStorage().(MultiNameStorage)
+	if !ok {
+		return nil, ErrLookupAllNotSupported
+	}
+	if !r.allows(channelName) {
+		return nil, ErrNotFound
+	}
+	if err := r.prepare(ctx); err != nil {
+		return nil, err
+	}
+	channels, err := storage.GetAllByChannelName(ctx, channelName)
+	if err != nil {
+		return nil, err
+	}
+	allowed := channels[:0]
+	for i := range channels {
+		if r.allowsChannel(&channels[i]) {
+			allowed = append(allowed, channels[i])
+		}
+	}
+	if len(allowed) == 0 {
+		return nil, ErrNotFound
+	}
+	return allowed, nil
+}
+
+func (s *InMemoryStorage) GetAllByChannelName(ctx context.Context, channelName string) ([]slack.Channel, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := s.idsByName[channelName]
+	if len(ids) == 0 {
+		return nil, ErrNotFound
+	}
+	winner := s.namesById[channelName]
+	channels := make([]slack.Channel, 0, len(ids))
+	for _, id := range ids {
+		channels = append(channels, cloneChannel(s.channels[id]))
+	}
+	sort.SliceStable(channels, func(i, j int) bool {
+		if (channels[i].ID == winner) != (channels[j].ID == winner) {
+			return channels[i].ID == winner
+		}
+		return channels[i].ID < channels[j].ID
+	})
+	return channels, nil
+}
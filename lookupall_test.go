@@ -0,0 +1,96 @@
+package slackcnr_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolverLookupAll(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C1", "general"),
+		newTestChannel("C2", "general"),
+		newTestChannel("C3", "random"),
+	}, "", nil).Once()
+	r := slackcnr.New(client, slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)))
+	ctx := context.Background()
+
+	winner, err := r.Lookup(ctx, "general")
+	require.NoError(t, err)
+	channels, err := r.LookupAll(ctx, "general")
+	require.NoError(t, err)
+	require.Len(t, channels, 2)
+	require.Equal(t, winner.ID, channels[0].ID, "the channel Lookup returns comes first")
+	require.ElementsMatch(t, []string{"C1", "C2"}, []string{channels[0].ID, channels[1].ID})
+
+	channels, err = r.LookupAll(ctx, "random")
+	require.NoError(t, err)
+	require.Len(t, channels, 1)
+	_, err = r.LookupAll(ctx, "unknown")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound)
+}
+
+func TestResolverLookupAll__Allowlist(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C1", "alerts"),
+		newTestChannel("C2", "general"),
+	}, "", nil).Once()
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+		slackcnr.WithAllowlist("alerts"),
+	)
+	ctx := context.Background()
+
+	channels, err := r.LookupAll(ctx, "alerts")
+	require.NoError(t, err)
+	require.Len(t, channels, 1)
+	_, err = r.LookupAll(ctx, "general")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound, "a cached channel not in the allowlist is not found")
+}
+
+func TestInMemoryStorage__GetAllByChannelNameAfterRenameAndDelete(t *testing.T) {
+	storage := slackcnr.NewInMemoryStorage(0)
+	ctx := context.Background()
+	require.NoError(t, storage.SetChannels(ctx, []slack.Channel{
+		newTestChannel("C1", "general"),
+		newTestChannel("C2", "general"),
+		newTestChannel("C3", "general"),
+	}))
+	require.NoError(t, storage.SetChannels(ctx, []slack.Channel{newTestChannel("C2", "renamed")}))
+	require.NoError(t, storage.DeleteChannels(ctx, "C3"))
+	channels, err := storage.GetAllByChannelName(ctx, "general")
+	require.NoError(t, err)
+	require.Equal(t, []string{"general"}, channelNames(channels))
+	require.Equal(t, "C1", channels[0].ID)
+}
+
+func TestInMemoryStorage__SameNamedChannelIsPromoted(t *testing.T) {
+	storage := slackcnr.NewInMemoryStorage(0)
+	ctx := context.Background()
+	require.NoError(t, storage.SetChannels(ctx, []slack.Channel{
+		newTestChannel("C1", "general"),
+		newTestChannel("C2", "general"),
+	}))
+	channel, err := storage.GetByChannelName(ctx, "general")
+	require.NoError(t, err)
+	require.NoError(t, storage.DeleteChannels(ctx, channel.ID))
+	promoted, err := storage.GetByChannelName(ctx, "general")
+	require.NoError(t, err)
+	require.NotEqual(t, channel.ID, promoted.ID)
+
+	require.NoError(t, storage.SetChannels(ctx, []slack.Channel{
+		newTestChannel("C3", "general"),
+		newTestChannel("C3", "renamed"),
+	}))
+	resolved, err := storage.GetByChannelName(ctx, "general")
+	require.NoError(t, err)
+	require.Equal(t, promoted.ID, resolved.ID)
+}
@@ -0,0 +1,143 @@
+package slackcnr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/slack-go/slack"
+)
+
+// BatchStorage is an optional interface for storages that can read many channels in a single round-trip,
+// such as storages backed by Redis or DynamoDB.
+type BatchStorage interface {
+	Storage
+	// BatchGetByChannelName returns the channels found by name, keyed by name. names not found are omitted.
+	BatchGetByChannelName(ctx context.Context, channelNames []string) (map[string]*slack.Channel, error)
+}
+
+var _ BatchStorage = (*InMemoryStorage)(nil)
+
+// WithLookupConcurrency sets how many names LookupMany reads from the cache storage concurrently,
+// when the storage does not implement BatchStorage. default is 1, reading the names serially.
+func WithLookupConcurrency(n int) ResolverOption {
+	return func(o *resolverOptions) {
+		o.lookupConcurrency = n
+	}
+}
+
+// LookupMany finds many channels by name, and returns them keyed by the given names.
+// names not found are omitted from the result without an error.
+// the cache is refreshed at most once, like a single Lookup, but a miss does not trigger a refresh.
+// with a BatchStorage, the names are read in a single round-trip, otherwise with the concurrency set by WithLookupConcurrency.
+// other errors are joined and annotated with the name, and the channels found are still returned.
+func (r *Resolver) LookupMany(ctx context.Context, channelNames []string) (map[string]*slack.Channel, error) {
+	ctx = ensureContext(ctx)
+	if err := r.prepare(ctx); err != nil {
+		return nil, err
+	}
+	normalized := make(map[string][]string, len(channelNames))
+	for _, name := range channelNames {
+		key := r.opts.normalizeName(name)
+		normalized[key] = append(normalized[key], name)
+	}
+	keys := make([]string, 0, len(normalized))
+	for key := range normalized {
+		keys = append(keys, key)
+	}
+
+	found := make(map[string]*slack.Channel, len(keys))
+	pending := keys
+	if s, ok := r.readStorage().(BatchStorage); ok {
+		channels, err := s.BatchGetByChannelName(ctx, keys)
+		if err != nil {
+			return nil, err
+		}
+		pending = pending[:0:0]
+		for _, key := range keys {
+			if !r.allows(key) {
+				continue
+			}
+			if channel, ok := channels[key]; ok && r.allowsChannel(channel) {
+				found[key] = channel
+			} else if !ok && r.opts.hasLookupFallback() {
+				pending = append(pending, key)
+			}
+		}
+	}
+	errs := r.getConcurrently(ctx, pending, found)
+
+	result := make(map[string]*slack.Channel, len(channelNames))
+	for key, names := range normalized {
+		channel, ok := found[key]
+		if !ok {
+			r.stats.misses.Add(int64(len(names)))
+			continue
+		}
+		r.stats.hits.Add(int64(len(names)))
+		for i, name := range names {
+			if i > 0 {
+				copied := cloneChannel(*channel)
+				channel = &copied
+			}
+			result[name] = channel
+		}
+	}
+	return result, errors.Join(errs...)
+}
+
+// hasLookupFallback reports whether a name missing from the storage may still resolve, by a shared prefix, separators or a rename.
+func (o *resolverOptions) hasLookupFallback() bool {
+	return o.indexSharedPrefixes || o.separatorInsensitive || o.trackRenames
+}
+
+// getConcurrently looks up the names with at most lookupConcurrency reads at once, and stores the channels found.
+func (r *Resolver) getConcurrently(ctx context.Context, keys []string, found map[string]*slack.Channel) []error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	sem := make(chan struct{}, max(r.opts.lookupConcurrency, 1))
+	for _, key := range keys {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			channel, err := r.get(ctx, key)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				found[key] = channel
+			case !errors.Is(err, ErrNotFound):
+				errs = append(errs, fmt.Errorf("lookup channel %s: %w", safeName(key), err))
+			}
+		}(key)
+	}
+	wg.Wait()
+	return errs
+}
+
+func (s *InMemoryStorage) BatchGetByChannelName(ctx context.Context, channelNames []string) (map[string]*slack.Channel, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	channels := make(map[string]*slack.Channel, len(channelNames))
+	for _, name := range channelNames {
+		id, ok := s.namesById[name]
+		if !ok {
+			continue
+		}
+		channel, ok := s.channels[id]
+		if !ok {
+			continue
+		}
+		s.used(id)
+		channel = cloneChannel(channel)
+		channels[name] = &channel
+	}
+	return channels, nil
+}
@@ -0,0 +1,96 @@
+package slackcnr_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/require"
+)
+
+type countingBatchStorage struct {
+	*slackcnr.InMemoryStorage
+	batches atomic.Int64
+	gets    atomic.Int64
+}
+
+func (s *countingBatchStorage) BatchGetByChannelName(ctx context.Context, channelNames []string) (map[string]*slack.Channel, error) {
+	s.batches.Add(1)
+	return s.InMemoryStorage.BatchGetByChannelName(ctx, channelNames)
+}
+
+func (s *countingBatchStorage) GetByChannelName(ctx context.Context, channelName string) (*slack.Channel, error) {
+	s.gets.Add(1)
+	return s.InMemoryStorage.GetByChannelName(ctx, channelName)
+}
+
+func TestResolverLookupMany__BatchStorage(t *testing.T) {
+	ctx := context.Background()
+	storage := &countingBatchStorage{InMemoryStorage: slackcnr.NewInMemoryStorage(0)}
+	require.NoError(t, storage.SetChannels(ctx, []slack.Channel{
+		newTestChannel("C012345678", "general"),
+		newTestChannel("C023456789", "random"),
+	}))
+	r := slackcnr.New(&mockSlackClient{t: t}, slackcnr.WithCacheStorage(storage))
+
+	channels, err := r.LookupMany(ctx, []string{"general", "random", "unknown", "general"})
+	require.NoError(t, err)
+	require.Len(t, channels, 2)
+	require.Equal(t, "C012345678", channels["general"].ID)
+	require.Equal(t, "C023456789", channels["random"].ID)
+	require.EqualValues(t, 1, storage.batches.Load())
+	require.EqualValues(t, 0, storage.gets.Load(), "no name is read one by one")
+}
+
+// slowStorage reads one channel at a time, and records the maximum number of concurrent reads.
+type slowStorage struct {
+	slackcnr.Storage
+	inFlight    atomic.Int64
+	maxInFlight atomic.Int64
+}
+
+func (s *slowStorage) GetByChannelName(ctx context.Context, channelName string) (*slack.Channel, error) {
+	n := s.inFlight.Add(1)
+	defer s.inFlight.Add(-1)
+	for {
+		current := s.maxInFlight.Load()
+		if n <= current || s.maxInFlight.CompareAndSwap(current, n) {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	if channelName == "broken" {
+		return nil, errors.New("connection reset")
+	}
+	return s.Storage.GetByChannelName(ctx, channelName)
+}
+
+func TestResolverLookupMany__Concurrent(t *testing.T) {
+	ctx := context.Background()
+	inMemory := slackcnr.NewInMemoryStorage(0)
+	var channels []slack.Channel
+	var names []string
+	for i := 0; i < 16; i++ {
+		name := fmt.Sprintf("channel-%02d", i)
+		channels = append(channels, newTestChannel(fmt.Sprintf("C%09d", i), name))
+		names = append(names, name)
+	}
+	require.NoError(t, inMemory.SetChannels(ctx, channels))
+	storage := &slowStorage{Storage: inMemory}
+	r := slackcnr.New(&mockSlackClient{t: t},
+		slackcnr.WithCacheStorage(storage),
+		slackcnr.WithLookupConcurrency(4),
+	)
+
+	found, err := r.LookupMany(ctx, append(names, "unknown", "broken"))
+	require.ErrorContains(t, err, "lookup channel broken: connection reset")
+	require.Len(t, found, 16, "the channels found are returned with the error")
+	require.Equal(t, "C000000003", found["channel-03"].ID)
+	require.Greater(t, storage.maxInFlight.Load(), int64(1))
+	require.LessOrEqual(t, storage.maxInFlight.Load(), int64(4))
+}
@@ -0,0 +1,121 @@
+package slackcnr
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+var _ Storage = (*LRUStorage)(nil)
+
+// LRUStorage is an in-memory storage that keeps at most maxEntries channels, evicting the least recently looked up one.
+// it is meant for lookup-heavy, memory-constrained use on very large workspaces:
+// a refresh updates the resident channels in place and admits the other channels as the least recently used,
+// so the channels looked up stay resident. it does not hold the full channel list and does not implement ListableStorage.
+// combine it with WithRefreshOnCacheMiss to fetch evicted channels again.
+type LRUStorage struct {
+	mu             sync.Mutex
+	maxEntries     int
+	expredDuration time.Duration
+	lastSetTime    time.Time
+	order          *list.List
+	byName         map[string]*list.Element
+	nameByID       map[string]string
+}
+
+type lruEntry struct {
+	name    string
+	channel slack.Channel
+}
+
+// NewLRUStorage creates a new LRU storage. if maxEntries is less than 1, it is treated as 1.
+// if expire is 0, it never expires.
+func NewLRUStorage(maxEntries int, expire time.Duration) *LRUStorage {
+	if maxEntries < 1 {
+		maxEntries = 1
+	}
+	return &LRUStorage{
+		maxEntries:     maxEntries,
+		expredDuration: expire,
+		order:          list.New(),
+		byName:         make(map[string]*list.Element),
+		nameByID:       make(map[string]string),
+	}
+}
+
+func (s *LRUStorage) SetChannels(ctx context.Context, channels []slack.Channel) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, channel := range channels {
+		if name, ok := s.nameByID[channel.ID]; ok && name != channel.Name {
+			// the previous name of a renamed channel no longer points to the channel.
+			s.remove(s.byName[name])
+		}
+		if elem, ok := s.byName[channel.Name]; ok {
+			if prev := elem.Value.(*lruEntry).channel.ID; prev != channel.ID {
+				delete(s.nameByID, prev)
+			}
+			elem.Value.(*lruEntry).channel = cloneChannel(channel)
+			s.nameByID[channel.ID] = channel.Name
+			continue
+		}
+		// only lookups promote a channel, so a write evicts the least recently used to admit a channel at the back.
+		for s.order.Len() >= s.maxEntries {
+			s.remove(s.order.Back())
+		}
+		s.byName[channel.Name] = s.order.PushBack(&lruEntry{name: channel.Name, channel: cloneChannel(channel)})
+		s.nameByID[channel.ID] = channel.Name
+	}
+
+	s.lastSetTime = now()
+	return nil
+}
+
+func (s *LRUStorage) remove(elem *list.Element) {
+	if elem == nil {
+		return
+	}
+	entry := s.order.Remove(elem).(*lruEntry)
+	delete(s.byName, entry.name)
+	if s.nameByID[entry.channel.ID] == entry.name {
+		delete(s.nameByID, entry.channel.ID)
+	}
+}
+
+func (s *LRUStorage) GetByChannelName(ctx context.Context, channelName string) (*slack.Channel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elem, ok := s.byName[channelName]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	s.order.MoveToFront(elem)
+	channel := cloneChannel(elem.Value.(*lruEntry).channel)
+	return &channel, nil
+}
+
+func (s *LRUStorage) NeedRefresh(ctx context.Context) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.lastSetTime.IsZero() {
+		return true
+	}
+	if s.expredDuration == 0 {
+		return false
+	}
+	return since(s.lastSetTime) > s.expredDuration
+}
+
+// Len returns the number of resident channels.
+func (s *LRUStorage) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.order.Len()
+}
@@ -0,0 +1,79 @@
+package slackcnr_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUStorage__Eviction(t *testing.T) {
+	storage := slackcnr.NewLRUStorage(2, 0)
+	ctx := context.Background()
+	require.True(t, storage.NeedRefresh(ctx))
+	require.NoError(t, storage.SetChannels(ctx, []slack.Channel{
+		newTestChannel("C1", "one"),
+		newTestChannel("C2", "two"),
+	}))
+	require.False(t, storage.NeedRefresh(ctx))
+
+	// touch "one" so that "two" becomes the least recently used.
+	_, err := storage.GetByChannelName(ctx, "one")
+	require.NoError(t, err)
+	require.NoError(t, storage.SetChannels(ctx, []slack.Channel{newTestChannel("C3", "three")}))
+	require.Equal(t, 2, storage.Len())
+
+	_, err = storage.GetByChannelName(ctx, "two")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound)
+	channel, err := storage.GetByChannelName(ctx, "one")
+	require.NoError(t, err)
+	require.Equal(t, "C1", channel.ID)
+	channel, err = storage.GetByChannelName(ctx, "three")
+	require.NoError(t, err)
+	require.Equal(t, "C3", channel.ID)
+}
+
+func TestLRUStorage__RefreshKeepsLookedUp(t *testing.T) {
+	storage := slackcnr.NewLRUStorage(2, 0)
+	ctx := context.Background()
+	channels := []slack.Channel{
+		newTestChannel("C1", "one"),
+		newTestChannel("C2", "two"),
+	}
+	require.NoError(t, storage.SetChannels(ctx, channels))
+	_, err := storage.GetByChannelName(ctx, "one")
+	require.NoError(t, err)
+
+	// a refresh writes the resident channels again, and a new one after them.
+	require.NoError(t, storage.SetChannels(ctx, append(channels, newTestChannel("C3", "three"))))
+	require.Equal(t, 2, storage.Len())
+	channel, err := storage.GetByChannelName(ctx, "one")
+	require.NoError(t, err, "the channel looked up stays resident")
+	require.Equal(t, "C1", channel.ID)
+	_, err = storage.GetByChannelName(ctx, "two")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound)
+}
+
+func TestLRUStorage__Rename(t *testing.T) {
+	storage := slackcnr.NewLRUStorage(10, 0)
+	ctx := context.Background()
+	require.NoError(t, storage.SetChannels(ctx, []slack.Channel{newTestChannel("C1", "old")}))
+	require.NoError(t, storage.SetChannels(ctx, []slack.Channel{newTestChannel("C1", "new")}))
+	require.Equal(t, 1, storage.Len())
+	_, err := storage.GetByChannelName(ctx, "old")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound)
+	channel, err := storage.GetByChannelName(ctx, "new")
+	require.NoError(t, err)
+	require.Equal(t, "C1", channel.ID)
+}
+
+func TestLRUStorage__Expire(t *testing.T) {
+	storage := slackcnr.NewLRUStorage(10, time.Millisecond)
+	ctx := context.Background()
+	require.NoError(t, storage.SetChannels(ctx, []slack.Channel{newTestChannel("C1", "test")}))
+	time.Sleep(5 * time.Millisecond)
+	require.True(t, storage.NeedRefresh(ctx))
+}
@@ -0,0 +1,110 @@
+package slackcnr
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// MetricsObserver receives resolver metrics.
+type MetricsObserver interface {
+	// ObserveRefresh is called after each refresh, with its duration and error.
+	ObserveRefresh(ctx context.Context, duration time.Duration, err error)
+	// ObserveCacheSize is called after each successful refresh, with the current cache size.
+	ObserveCacheSize(ctx context.Context, size CacheSize)
+}
+
+// CacheSize is the size of the cache storage.
+type CacheSize struct {
+	// Channels is the number of cached channels.
+	Channels int64
+	// ApproxBytes is the approximate memory footprint in bytes. 0 if unknown.
+	ApproxBytes int64
+}
+
+// SizedStorage is an optional interface for storages that report their size.
+type SizedStorage interface {
+	Storage
+	CacheSize(ctx context.Context) (CacheSize, error)
+}
+
+// WithMetricsObserver sets the observer of resolver metrics.
+func WithMetricsObserver(observer MetricsObserver) ResolverOption {
+	return func(o *resolverOptions) {
+		o.metricsObserver = observer
+	}
+}
+
+func (r *Resolver) observeRefresh(ctx context.Context, started time.Time, err error) {
+	observer := r.opts.metricsObserver
+	if observer == nil {
+		return
+	}
+	observer.ObserveRefresh(ctx, time.Since(started), err)
+	if err != nil {
+		return
+	}
+	size := CacheSize{
+		Channels: r.stats.cachedChannels.Load(),
+	}
+	if s, ok := r.cacheStorage().(SizedStorage); ok {
+		if storageSize, err := s.CacheSize(ctx); err == nil {
+			size = storageSize
+		} else {
+			r.logger(ctx).WarnContext(ctx, "failed to get cache size", "error", err)
+		}
+	}
+	observer.ObserveCacheSize(ctx, size)
+}
+
+// StorageMetricsObserver is an optional interface for metrics observers that also time the cache storage operations,
+// to tell a slow storage backend apart from slow Slack API calls.
+type StorageMetricsObserver interface {
+	MetricsObserver
+	// ObserveStorage is called after each storage operation the resolver makes,
+	// with op "GetByChannelName", "SetChannels", "ReplaceChannels" or "List". a channel not found is not an error.
+	ObserveStorage(ctx context.Context, op string, duration time.Duration, err error)
+}
+
+func (r *Resolver) observeStorage(ctx context.Context, op string, started time.Time, err error) {
+	observer, ok := r.opts.metricsObserver.(StorageMetricsObserver)
+	if !ok {
+		return
+	}
+	if errors.Is(err, ErrNotFound) {
+		err = nil
+	}
+	observer.ObserveStorage(ctx, op, time.Since(started), err)
+}
+
+// getByChannelName reads a channel from the read storage, observing the duration.
+func (r *Resolver) getByChannelName(ctx context.Context, channelName string) (*slack.Channel, error) {
+	started := time.Now()
+	channel, err := r.readStorage().GetByChannelName(ctx, channelName)
+	r.observeStorage(ctx, "GetByChannelName", started, err)
+	return channel, err
+}
+
+// writeChannels writes the channels to the cache storage with SetChannels, observing the duration.
+// channels already cached and not given are kept, use replaceChannels to drop them.
+func (r *Resolver) writeChannels(ctx context.Context, channels []slack.Channel) error {
+	started := time.Now()
+	err := r.cacheStorage().SetChannels(ctx, channels)
+	r.observeStorage(ctx, "SetChannels", started, err)
+	return err
+}
+
+// replaceChannels replaces all channels of the cache storage, observing the duration.
+// it falls back to writeChannels if the storage does not implement ReplaceableStorage, such as after SetStorage.
+func (r *Resolver) replaceChannels(ctx context.Context, channels []slack.Channel) error {
+	s, ok := r.cacheStorage().(ReplaceableStorage)
+	if !ok {
+		return r.writeChannels(ctx, channels)
+	}
+	started := time.Now()
+	err := s.ReplaceChannels(ctx, channels)
+	r.observeStorage(ctx, "ReplaceChannels", started, err)
+	return err
+}
@@ -0,0 +1,91 @@
+package slackcnr_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingObserver struct {
+	refreshes []error
+	sizes     []slackcnr.CacheSize
+}
+
+func (o *recordingObserver) ObserveRefresh(ctx context.Context, duration time.Duration, err error) {
+	o.refreshes = append(o.refreshes, err)
+}
+
+func (o *recordingObserver) ObserveCacheSize(ctx context.Context, size slackcnr.CacheSize) {
+	o.sizes = append(o.sizes, size)
+}
+
+func TestResolverRefresh__MetricsObserver(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C012345678", "test"),
+		newTestChannel("C023456789", "test2"),
+	}, "", nil).Once()
+	observer := &recordingObserver{}
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+		slackcnr.WithMetricsObserver(observer),
+	)
+	require.NoError(t, r.Refresh(context.Background()))
+	require.Equal(t, []error{nil}, observer.refreshes)
+	require.Len(t, observer.sizes, 1)
+	require.EqualValues(t, 2, observer.sizes[0].Channels)
+	require.Greater(t, observer.sizes[0].ApproxBytes, int64(0))
+}
+
+type storageOp struct {
+	op  string
+	err error
+}
+
+type recordingStorageObserver struct {
+	recordingObserver
+	mu  sync.Mutex
+	ops []storageOp
+}
+
+func (o *recordingStorageObserver) ObserveStorage(ctx context.Context, op string, duration time.Duration, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.ops = append(o.ops, storageOp{op: op, err: err})
+}
+
+func TestResolver__StorageMetricsObserver(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C012345678", "test"),
+	}, "", nil).Once()
+	observer := &recordingStorageObserver{}
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+		slackcnr.WithMetricsObserver(observer),
+	)
+	ctx := context.Background()
+	require.NoError(t, r.Refresh(ctx))
+	require.Equal(t, []storageOp{{op: "SetChannels"}}, observer.ops)
+
+	observer.ops = nil
+	_, err := r.Lookup(ctx, "test")
+	require.NoError(t, err)
+	_, err = r.Lookup(ctx, "unknown")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound)
+	_, err = r.List(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []storageOp{
+		{op: "GetByChannelName"},
+		{op: "GetByChannelName"},
+		{op: "List"},
+	}, observer.ops, "a miss is not reported as an error")
+}
@@ -0,0 +1,79 @@
+package slackcnr
+
+import (
+	"strings"
+
+	"github.com/slack-go/slack"
+	"golang.org/x/text/unicode/norm"
+)
+
+// WithUnicodeNormalization normalizes channel names to Unicode NFC, both when indexing and querying.
+// visually identical names with different code points, such as decomposed and composed forms, resolve to the same channel.
+func WithUnicodeNormalization() ResolverOption {
+	return func(o *resolverOptions) {
+		o.unicodeNormalization = true
+	}
+}
+
+// WithNameNormalizers applies the normalizers to channel names left to right, both when indexing and querying,
+// such as WithNameNormalizers(TrimHash, LowerCase) to resolve "#General" to "general".
+// they are applied after WithUnicodeNormalization. a KeyedStorage indexes the normalized names as additional keys,
+// and the cached channels keep their names on Slack. with other storages, the cached channels have the normalized names.
+// calling it again appends the normalizers to the chain.
+func WithNameNormalizers(normalizers ...func(string) string) ResolverOption {
+	return func(o *resolverOptions) {
+		o.nameNormalizers = append(o.nameNormalizers, normalizers...)
+	}
+}
+
+// TrimHash is a name normalizer removing the leading "#" of a channel name.
+func TrimHash(name string) string {
+	return strings.TrimPrefix(name, "#")
+}
+
+// LowerCase is a name normalizer lowercasing a channel name.
+func LowerCase(name string) string {
+	return strings.ToLower(name)
+}
+
+// NFC is a name normalizer converting a channel name to Unicode NFC, like WithUnicodeNormalization.
+func NFC(name string) string {
+	return norm.NFC.String(name)
+}
+
+func (o *resolverOptions) normalizesNames() bool {
+	return o.unicodeNormalization || len(o.nameNormalizers) > 0
+}
+
+func (o *resolverOptions) normalizeName(name string) string {
+	if o.unicodeNormalization {
+		name = NFC(name)
+	}
+	for _, normalize := range o.nameNormalizers {
+		name = normalize(name)
+	}
+	return name
+}
+
+// storedChannels returns the channels as written to the cache storage.
+// a KeyedStorage indexes the normalized names by lookupKeyFunc, so the channels keep their names,
+// other storages get the channels with normalized names.
+func (r *Resolver) storedChannels(channels []slack.Channel) []slack.Channel {
+	if _, ok := r.cacheStorage().(KeyedStorage); ok {
+		return channels
+	}
+	return r.opts.normalizeChannels(channels)
+}
+
+// normalizeChannels returns the channels with normalized names. the given slice is not modified.
+func (o *resolverOptions) normalizeChannels(channels []slack.Channel) []slack.Channel {
+	if !o.normalizesNames() {
+		return channels
+	}
+	normalized := make([]slack.Channel, len(channels))
+	for i, channel := range channels {
+		channel.Name = o.normalizeName(channel.Name)
+		normalized[i] = channel
+	}
+	return normalized
+}
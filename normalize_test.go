@@ -0,0 +1,92 @@
+package slackcnr_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolverLookup__UnicodeNormalization(t *testing.T) {
+	const (
+		decomposed = "cafe\u0301-channel" // e + combining acute accent
+		composed   = "caf\u00e9-channel"
+	)
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C012345678", decomposed),
+	}, "", nil).Once()
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+		slackcnr.WithUnicodeNormalization(),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	for _, name := range []string{composed, decomposed} {
+		channel, err := r.Lookup(ctx, name)
+		require.NoError(t, err, name)
+		require.Equal(t, "C012345678", channel.ID)
+		require.Equal(t, decomposed, channel.Name, "the channel keeps its name on Slack")
+	}
+}
+
+func TestResolverLookup__NameNormalizers(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C012345678", "General"),
+		newTestChannel("C023456789", "#Random"),
+	}, "", nil).Once()
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+		slackcnr.WithNameNormalizers(slackcnr.TrimHash, slackcnr.LowerCase),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	cases := []struct {
+		query    string
+		expected string
+	}{
+		{query: "general", expected: "C012345678"},
+		{query: "#GENERAL", expected: "C012345678"},
+		{query: "random", expected: "C023456789"},
+		{query: "#Random", expected: "C023456789"},
+	}
+	for _, c := range cases {
+		channel, err := r.Lookup(ctx, c.query)
+		require.NoError(t, err, c.query)
+		require.Equal(t, c.expected, channel.ID, c.query)
+	}
+	channel, err := r.Lookup(ctx, "general")
+	require.NoError(t, err)
+	require.Equal(t, "General", channel.Name, "the channel keeps its name on Slack")
+	names, err := r.ListNames(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"#Random", "General", "general", "random"}, names, "the normalized names are indexed as keys")
+}
+
+func TestNameNormalizers(t *testing.T) {
+	require.Equal(t, "general", slackcnr.TrimHash("#general"))
+	require.Equal(t, "general", slackcnr.LowerCase("GeNeRaL"))
+	require.Equal(t, "caf\u00e9", slackcnr.NFC("cafe\u0301"))
+}
+
+func TestResolverLookup__NameNormalizersWithoutKeyedStorage(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C012345678", "General"),
+	}, "", nil).Once()
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(slackcnr.NewLRUStorage(10, 0)),
+		slackcnr.WithNameNormalizers(slackcnr.LowerCase),
+	)
+	channel, err := r.Lookup(context.Background(), "GENERAL")
+	require.NoError(t, err)
+	require.Equal(t, "general", channel.Name, "a storage without keys stores the normalized name")
+}
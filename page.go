@@ -0,0 +1,42 @@
+package slackcnr
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/slack-go/slack"
+)
+
+// Source is the API a page of channels is fetched from by FetchPage.
+type Source string
+
+const (
+	// SourceUserConversations fetches the channels the bot is a member of with users.conversations API.
+	SourceUserConversations Source = Source(sourceUserConversations)
+	// SourcePublicChannels fetches the public channels with conversations.list API.
+	SourcePublicChannels Source = Source(sourcePublicChannels)
+)
+
+// FetchPage performs a single API call for the source, and returns the page and the cursor of the next page,
+// empty on the last page. it is a building block for custom refresh loops: the page is not cached,
+// and a rate limit is not retried but returned as *slack.RateLimitedError for the caller to decide.
+// the request parameters, such as the batch size and the conversation types, follow the resolver options.
+// while pinned by Pin, it calls no API and returns ErrNotFound.
+func (r *Resolver) FetchPage(ctx context.Context, source Source, cursor string) (channels []slack.Channel, nextCursor string, err error) {
+	ctx = ensureContext(ctx)
+	if r.isPinned() {
+		return nil, "", ErrNotFound
+	}
+	var fetch fetchPageFunc
+	switch source {
+	case SourceUserConversations:
+		fetch = r.fetchUserConversationsPage
+	case SourcePublicChannels:
+		fetch = r.fetchPublicChannelsPage
+	default:
+		return nil, "", fmt.Errorf("unknown source %q", source)
+	}
+	callCtx, cancel := r.callContext(ctx)
+	defer cancel()
+	return fetch(callCtx, cursor)
+}
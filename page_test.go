@@ -0,0 +1,55 @@
+package slackcnr_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolverFetchPage(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, &slack.GetConversationsForUserParameters{
+		Cursor: "cursor1",
+		Limit:  100,
+	}).Return([]slack.Channel{newTestChannel("C012345678", "member")}, "cursor2", nil).Once()
+	client.On("GetConversationsContext", mock.Anything, &slack.GetConversationsParameters{
+		Limit: 100,
+	}).Return([]slack.Channel{newTestChannel("C023456789", "public")}, "", nil).Once()
+	storage := slackcnr.NewInMemoryStorage(0)
+	r := slackcnr.New(client, slackcnr.WithCacheStorage(storage), slackcnr.WithBatchSize(100))
+	ctx := context.Background()
+
+	channels, nextCursor, err := r.FetchPage(ctx, slackcnr.SourceUserConversations, "cursor1")
+	require.NoError(t, err)
+	require.Equal(t, "cursor2", nextCursor)
+	require.Equal(t, []string{"member"}, channelNames(channels))
+
+	channels, nextCursor, err = r.FetchPage(ctx, slackcnr.SourcePublicChannels, "")
+	require.NoError(t, err)
+	require.Empty(t, nextCursor)
+	require.Equal(t, []string{"public"}, channelNames(channels))
+
+	require.True(t, storage.NeedRefresh(ctx), "the pages are not cached")
+
+	_, _, err = r.FetchPage(ctx, slackcnr.Source("unknown"), "")
+	require.Error(t, err)
+}
+
+func TestResolverFetchPage__RateLimited(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).
+		Return([]slack.Channel{}, "", &slack.RateLimitedError{RetryAfter: time.Second}).Once()
+	r := slackcnr.New(client, slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)))
+
+	_, _, err := r.FetchPage(context.Background(), slackcnr.SourceUserConversations, "")
+	var rateLimited *slack.RateLimitedError
+	require.ErrorAs(t, err, &rateLimited)
+	require.Equal(t, time.Second, rateLimited.RetryAfter)
+}
@@ -0,0 +1,71 @@
+package slackcnr
+
+import (
+	"encoding/gob"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// inMemorySnapshotVersion is the version of the format written by InMemoryStorage.SaveSnapshot.
+const inMemorySnapshotVersion = 1
+
+// inMemorySnapshot is the gob encoded content of InMemoryStorage.SaveSnapshot.
+type inMemorySnapshot struct {
+	Version     int
+	LastSetTime time.Time
+	SyncState   SyncState
+	Channels    []slack.Channel
+}
+
+// SaveSnapshot writes the cached channels, the time they were set and the sync state as a compact gob snapshot,
+// so that a process can persist its cache across restarts without an external store. restore it with LoadSnapshot.
+func (s *InMemoryStorage) SaveSnapshot(w io.Writer) error {
+	s.mu.RLock()
+	snapshot := inMemorySnapshot{
+		Version:     inMemorySnapshotVersion,
+		LastSetTime: s.lastSetTime,
+		SyncState:   s.syncState,
+		Channels:    make([]slack.Channel, 0, len(s.channels)),
+	}
+	for _, channel := range s.channels {
+		snapshot.Channels = append(snapshot.Channels, cloneChannel(channel))
+	}
+	s.mu.RUnlock()
+	sort.Slice(snapshot.Channels, func(i, j int) bool {
+		return snapshot.Channels[i].ID < snapshot.Channels[j].ID
+	})
+	if err := gob.NewEncoder(w).Encode(snapshot); err != nil {
+		return fmt.Errorf("encode snapshot: %w", err)
+	}
+	return nil
+}
+
+// LoadSnapshot replaces the cached channels with the ones of a snapshot written by SaveSnapshot.
+// the time the channels were set is restored, so that the expiry of the cache survives a restart.
+func (s *InMemoryStorage) LoadSnapshot(r io.Reader) error {
+	var snapshot inMemorySnapshot
+	if err := gob.NewDecoder(r).Decode(&snapshot); err != nil {
+		return fmt.Errorf("decode snapshot: %w", err)
+	}
+	if snapshot.Version != inMemorySnapshotVersion {
+		return fmt.Errorf("unsupported snapshot version %d", snapshot.Version)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.channels = make(map[string]slack.Channel)
+	s.namesById = make(map[string]string)
+	s.keysById = make(map[string][]string)
+	s.idsByName = make(map[string][]string)
+	if s.ages != nil {
+		s.ages = make(map[string]*entryAge)
+	}
+	s.set(snapshot.Channels)
+	s.lastSetTime = snapshot.LastSetTime
+	s.syncState = snapshot.SyncState
+	return nil
+}
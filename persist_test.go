@@ -0,0 +1,48 @@
+package slackcnr_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryStorage__SnapshotRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	setAt := time.Now().Add(-30 * time.Minute)
+	slackcnr.SetNow(t, func() time.Time { return setAt })
+	storage := slackcnr.NewInMemoryStorage(time.Hour)
+	channel := newTestChannel("C012345678", "general")
+	channel.Topic.Value = "announcements"
+	channel.IsArchived = true
+	require.NoError(t, storage.SetChannels(ctx, []slack.Channel{
+		channel,
+		newTestChannel("C023456789", "random"),
+	}))
+	require.NoError(t, storage.SetSyncState(ctx, slackcnr.SyncState{Cursors: map[string]string{"users.conversations": "cursor"}}))
+
+	var buf bytes.Buffer
+	require.NoError(t, storage.SaveSnapshot(&buf))
+
+	restored := slackcnr.NewInMemoryStorage(time.Hour)
+	require.NoError(t, restored.LoadSnapshot(&buf))
+	got, err := restored.GetByChannelName(ctx, "general")
+	require.NoError(t, err)
+	require.Equal(t, channel, *got)
+	_, err = restored.GetByChannelName(ctx, "random")
+	require.NoError(t, err)
+	state, err := restored.GetSyncState(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "cursor", state.Cursors["users.conversations"])
+
+	slackcnr.SetNow(t, func() time.Time { return setAt.Add(59 * time.Minute) })
+	require.False(t, restored.NeedRefresh(ctx), "the refresh time is preserved")
+	slackcnr.SetNow(t, func() time.Time { return setAt.Add(61 * time.Minute) })
+	require.True(t, restored.NeedRefresh(ctx))
+
+	require.Error(t, restored.LoadSnapshot(bytes.NewBufferString("garbage")))
+}
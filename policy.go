@@ -0,0 +1,90 @@
+package slackcnr
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// LookupPolicy decides what a lookup does when the cache needs a refresh.
+// the zero value reproduces the default behavior: refresh and wait for it without a bound, and fail if the refresh fails.
+type LookupPolicy struct {
+	// PreferFresh makes a lookup try to refresh before reading. it only matters with StaleOK,
+	// since without StaleOK a lookup always refreshes first.
+	PreferFresh bool
+	// StaleOK allows serving the stale cache instead of failing.
+	// without PreferFresh, the stale cache is served immediately and refreshed in the background (stale-while-revalidate),
+	// except for a cache never populated, which is refreshed before reading.
+	// with PreferFresh, the stale cache is served when the refresh fails or does not complete within MaxWait.
+	// while another refresh is in flight, the stale cache is served without waiting for it.
+	StaleOK bool
+	// MaxWait bounds how long a lookup waits for the refresh. 0 means no bound other than the context.
+	// when exceeded, the refresh keeps running in the background,
+	// and the lookup serves the stale cache with StaleOK, or fails with ErrRefreshTimeout otherwise.
+	MaxWait time.Duration
+	// BackgroundTimeout bounds a refresh that outlives the lookup, started by stale-while-revalidate or exceeding MaxWait,
+	// so that a stalled API call does not hold the refresh lock forever. 0 means 5 minutes.
+	BackgroundTimeout time.Duration
+}
+
+// defaultBackgroundRefreshTimeout is the bound of a background refresh when LookupPolicy.BackgroundTimeout is 0.
+const defaultBackgroundRefreshTimeout = 5 * time.Minute
+
+// backgroundContext returns a context for a refresh that outlives the lookup of ctx, bounded by BackgroundTimeout.
+func (p LookupPolicy) backgroundContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := p.BackgroundTimeout
+	if timeout <= 0 {
+		timeout = defaultBackgroundRefreshTimeout
+	}
+	return context.WithTimeout(context.WithoutCancel(ctx), timeout)
+}
+
+// ErrRefreshTimeout is returned by a lookup when the refresh did not complete within LookupPolicy.MaxWait.
+var ErrRefreshTimeout = errors.New("refresh did not complete within the max wait of the lookup policy")
+
+// WithLookupPolicy sets the policy of lookups when the cache needs a refresh.
+// WithSkipRefreshWhenInsufficientDeadline is applied before the policy.
+func WithLookupPolicy(policy LookupPolicy) ResolverOption {
+	return func(o *resolverOptions) {
+		o.lookupPolicy = policy
+	}
+}
+
+// refreshWithin refreshes the cache, waiting at most maxWait for it if maxWait is greater than 0.
+func (r *Resolver) refreshWithin(ctx context.Context, maxWait time.Duration) error {
+	if maxWait <= 0 {
+		return r.Refresh(ctx)
+	}
+	done := make(chan error, 1)
+	bg, cancel := r.opts.lookupPolicy.backgroundContext(ctx)
+	go func() {
+		defer cancel()
+		// the refresh outlives the lookup if it takes longer than maxWait.
+		done <- r.Refresh(bg)
+	}()
+	timer := time.NewTimer(maxWait)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		return ErrRefreshTimeout
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// revalidate starts a background refresh, unless one started by revalidate is already running.
+func (r *Resolver) revalidate(ctx context.Context) {
+	if !r.revalidating.CompareAndSwap(false, true) {
+		return
+	}
+	ctx, cancel := r.opts.lookupPolicy.backgroundContext(ctx)
+	go func() {
+		defer r.revalidating.Store(false)
+		defer cancel()
+		if err := r.Refresh(ctx); err != nil {
+			r.logger(ctx).WarnContext(ctx, "background refresh failed", "error", err)
+		}
+	}()
+}
@@ -0,0 +1,142 @@
+package slackcnr_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newStaleStorage returns a storage holding "stale" that needs a refresh.
+func newStaleStorage(t *testing.T) *slackcnr.InMemoryStorage {
+	t.Helper()
+	storage := slackcnr.NewInMemoryStorage(time.Millisecond)
+	require.NoError(t, storage.SetChannels(context.Background(), []slack.Channel{newTestChannel("C012345678", "stale")}))
+	time.Sleep(5 * time.Millisecond)
+	require.True(t, storage.NeedRefresh(context.Background()))
+	return storage
+}
+
+func TestResolverLookup__LookupPolicy(t *testing.T) {
+	errRefresh := errors.New("refresh failed")
+	cases := []struct {
+		name     string
+		policy   slackcnr.LookupPolicy
+		block    time.Duration
+		err      error
+		expected error
+	}{
+		{name: "default fails on refresh error", err: errRefresh, expected: errRefresh},
+		{name: "prefer fresh serves stale on refresh error", policy: slackcnr.LookupPolicy{PreferFresh: true, StaleOK: true}, err: errRefresh},
+		{name: "max wait fails on slow refresh", policy: slackcnr.LookupPolicy{MaxWait: 10 * time.Millisecond}, block: time.Second, expected: slackcnr.ErrRefreshTimeout},
+		{name: "max wait serves stale on slow refresh", policy: slackcnr.LookupPolicy{PreferFresh: true, StaleOK: true, MaxWait: 10 * time.Millisecond}, block: time.Second},
+		{name: "max wait returns the refreshed cache", policy: slackcnr.LookupPolicy{MaxWait: time.Second}},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			client := &mockSlackClient{t: t}
+			release := make(chan struct{})
+			defer close(release)
+			client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+				select {
+				case <-time.After(c.block):
+				case <-release:
+				}
+			}).Return([]slack.Channel{newTestChannel("C012345678", "stale")}, "", c.err).Once()
+			r := slackcnr.New(client,
+				slackcnr.WithCacheStorage(newStaleStorage(t)),
+				slackcnr.WithLookupPolicy(c.policy),
+			)
+			started := time.Now()
+			channel, err := r.Lookup(context.Background(), "stale")
+			require.Less(t, time.Since(started), 500*time.Millisecond)
+			if c.expected != nil {
+				require.ErrorIs(t, err, c.expected)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "C012345678", channel.ID)
+		})
+	}
+}
+
+func TestResolverLookup__LookupPolicyStaleWhileRevalidate(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	refreshed := make(chan struct{})
+	var once sync.Once
+	// the storage expires in a millisecond, so every lookup may start another background refresh.
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+		once.Do(func() { close(refreshed) })
+	}).Return([]slack.Channel{newTestChannel("C023456789", "fresh")}, "", nil)
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(newStaleStorage(t)),
+		slackcnr.WithLookupPolicy(slackcnr.LookupPolicy{StaleOK: true}),
+	)
+	ctx := context.Background()
+
+	channel, err := r.Lookup(ctx, "stale")
+	require.NoError(t, err, "the stale cache is served immediately")
+	require.Equal(t, "C012345678", channel.ID)
+	select {
+	case <-refreshed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the background refresh did not run")
+	}
+	require.Eventually(t, func() bool {
+		_, err := r.Lookup(ctx, "fresh")
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestResolverLookup__StaleWhileRevalidateColdStart(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C012345678", "general"),
+	}, "", nil).Once()
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+		slackcnr.WithLookupPolicy(slackcnr.LookupPolicy{StaleOK: true}),
+	)
+
+	channel, err := r.Lookup(context.Background(), "general")
+	require.NoError(t, err, "a cache never populated is refreshed before reading")
+	require.Equal(t, "C012345678", channel.ID)
+	require.Zero(t, r.Stats().Degraded)
+}
+
+func TestResolverLookup__BackgroundRefreshTimeout(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	stalled := make(chan struct{})
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		// a stalled API call, that returns only when its context is done.
+		close(stalled)
+		<-args.Get(0).(context.Context).Done()
+	}).Return([]slack.Channel(nil), "", context.DeadlineExceeded).Once()
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C023456789", "fresh"),
+	}, "", nil).Once()
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(newStaleStorage(t)),
+		slackcnr.WithLookupPolicy(slackcnr.LookupPolicy{StaleOK: true, BackgroundTimeout: 50 * time.Millisecond}),
+	)
+	ctx := context.Background()
+
+	_, err := r.Lookup(ctx, "stale")
+	require.NoError(t, err, "the stale cache is served immediately")
+	<-stalled
+	refreshCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	channel, err := r.RefreshAndLookup(refreshCtx, "fresh")
+	require.NoError(t, err, "the stalled background refresh does not hold the refresh forever")
+	require.Equal(t, "C023456789", channel.ID)
+}
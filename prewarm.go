@@ -0,0 +1,31 @@
+package slackcnr
+
+import (
+	"context"
+	"errors"
+)
+
+// Prewarm refreshes the cache storage once, then checks that each of names resolves,
+// such as to validate the channel names of the configuration at startup.
+// it returns the names that did not resolve in the given order, including those excluded by WithAllowlist.
+// a non-nil error is a refresh or cache storage failure, not a missing name.
+func (r *Resolver) Prewarm(ctx context.Context, names []string) (missing []string, err error) {
+	ctx = ensureContext(ctx)
+	if err := r.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		channelName := r.opts.normalizeName(name)
+		if !r.allows(channelName) {
+			missing = append(missing, name)
+			continue
+		}
+		if _, err := r.get(ctx, channelName); err != nil {
+			if !errors.Is(err, ErrNotFound) {
+				return nil, err
+			}
+			missing = append(missing, name)
+		}
+	}
+	return missing, nil
+}
@@ -0,0 +1,42 @@
+package slackcnr_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolverPrewarm(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	ctx := context.Background()
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C012345678", "general"),
+		newTestChannel("C023456789", "random"),
+	}, "", nil).Once()
+	r := slackcnr.New(client)
+
+	missing, err := r.Prewarm(ctx, []string{"general", "typo-channel", "random", "deleted"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"typo-channel", "deleted"}, missing)
+
+	channel, err := r.Lookup(ctx, "random")
+	require.NoError(t, err, "the cache is warmed")
+	require.Equal(t, "C023456789", channel.ID)
+}
+
+func TestResolverPrewarm__RefreshError(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel(nil), "", errors.New("internal_error")).Once()
+	r := slackcnr.New(client)
+
+	missing, err := r.Prewarm(context.Background(), []string{"general"})
+	require.Error(t, err)
+	require.Nil(t, missing)
+}
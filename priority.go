@@ -0,0 +1,34 @@
+package slackcnr
+
+import "github.com/slack-go/slack"
+
+// ChannelPriorityStorage is an optional interface for storages that resolve name collisions by priority.
+type ChannelPriorityStorage interface {
+	Storage
+	// SetChannelPriority sets the function that reports whether a should be preferred over b, for channels with the same name.
+	SetChannelPriority(prefer func(a, b *slack.Channel) bool)
+}
+
+// WithChannelPriority sets how to resolve channels with the same name.
+// prefer reports whether a should be preferred over b. by default, the channel stored last wins.
+// it is applied to the cache storage if it implements ChannelPriorityStorage, NewChecked returns an error otherwise.
+func WithChannelPriority(prefer func(a, b *slack.Channel) bool) ResolverOption {
+	return func(o *resolverOptions) {
+		o.channelPriority = prefer
+	}
+}
+
+// WithPreferNewest prefers the most recently created non-archived channel among channels with the same name.
+// with WithExcludeArchived, archived channels are never fetched, so simply the newest channel wins.
+// otherwise, a non-archived channel wins over an archived one regardless of the created timestamp.
+func WithPreferNewest() ResolverOption {
+	return WithChannelPriority(PreferNewest)
+}
+
+// PreferNewest reports whether a is preferred over b, preferring non-archived channels, then greater Created timestamp.
+func PreferNewest(a, b *slack.Channel) bool {
+	if a.IsArchived != b.IsArchived {
+		return !a.IsArchived
+	}
+	return a.Created > b.Created
+}
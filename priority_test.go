@@ -0,0 +1,71 @@
+package slackcnr_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolverLookup__PreferNewest(t *testing.T) {
+	older := newTestChannel("C_OLD", "duplicated")
+	older.Created = slack.JSONTime(1600000000)
+	newer := newTestChannel("C_NEW", "duplicated")
+	newer.Created = slack.JSONTime(1700000000)
+	newestArchived := newTestChannel("C_ARCHIVED", "duplicated")
+	newestArchived.Created = slack.JSONTime(1800000000)
+	newestArchived.IsArchived = true
+
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newer, newestArchived,
+	}, "next", nil).Once()
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		older,
+	}, "", nil).Once()
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+		slackcnr.WithPreferNewest(),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	channel, err := r.Lookup(ctx, "duplicated")
+	require.NoError(t, err)
+	require.Equal(t, "C_NEW", channel.ID)
+}
+
+func TestNewChecked__ChannelPriorityNotSupported(t *testing.T) {
+	_, err := slackcnr.NewChecked(&mockSlackClient{t: t},
+		slackcnr.WithCacheStorage(&mockStorage{t: t}),
+		slackcnr.WithPreferNewest(),
+	)
+	require.Error(t, err)
+}
+
+func TestInMemoryStorage__ChannelPriorityIndependentOfBatchOrder(t *testing.T) {
+	channelA := newTestChannel("CA", "duplicated")
+	channelA.Created = slack.JSONTime(1700000000)
+	archivedA := channelA
+	archivedA.IsArchived = true
+	channelB := newTestChannel("CB", "duplicated")
+	channelB.Created = slack.JSONTime(1600000000)
+
+	for _, batch := range [][]slack.Channel{
+		{channelB, archivedA},
+		{archivedA, channelB},
+	} {
+		ctx := context.Background()
+		storage := slackcnr.NewInMemoryStorage(0)
+		storage.SetChannelPriority(slackcnr.PreferNewest)
+		require.NoError(t, storage.SetChannels(ctx, []slack.Channel{channelA}))
+		require.NoError(t, storage.SetChannels(ctx, batch))
+		channel, err := storage.GetByChannelName(ctx, "duplicated")
+		require.NoError(t, err)
+		require.Equal(t, "CB", channel.ID, "the archived channel loses regardless of the batch order")
+	}
+}
@@ -0,0 +1,76 @@
+package slackcnr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+// channelRefPattern matches channel mentions in Slack message text, such as <#C012345678> and <#C012345678|general>.
+var channelRefPattern = regexp.MustCompile(`<#([CGD][A-Z0-9]+)(?:\|[^>]*)?>`)
+
+// ResolveRefsFromText extracts all channel mentions like <#C012345678|general> from the text,
+// and resolves each by ID with LookupByID. the result is keyed by channel ID, unresolvable mentions map to nil.
+func (r *Resolver) ResolveRefsFromText(ctx context.Context, text string) (map[string]*slack.Channel, error) {
+	ctx = ensureContext(ctx)
+	refs := make(map[string]*slack.Channel)
+	for _, match := range channelRefPattern.FindAllStringSubmatch(text, -1) {
+		id := match[1]
+		if _, ok := refs[id]; ok {
+			continue
+		}
+		channel, err := r.LookupByID(ctx, id)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+		refs[id] = channel
+	}
+	return refs, nil
+}
+
+// ErrInvalidPermalink is returned when a permalink is not a Slack archives URL.
+var ErrInvalidPermalink = errors.New("invalid slack permalink")
+
+// LookupByPermalink resolves the channel of a Slack permalink,
+// such as https://team.slack.com/archives/C012345678/p1700000000000000 or a thread reply link with thread_ts.
+// the channel is resolved by ID with LookupByID. malformed permalinks return an error wrapping ErrInvalidPermalink.
+func (r *Resolver) LookupByPermalink(ctx context.Context, permalink string) (*slack.Channel, error) {
+	channelID, err := parsePermalink(permalink)
+	if err != nil {
+		return nil, err
+	}
+	return r.LookupByID(ctx, channelID)
+}
+
+// parsePermalink extracts the channel ID from the /archives/<channel ID>[/p<ts>] path of a permalink.
+func parsePermalink(permalink string) (string, error) {
+	u, err := url.Parse(permalink)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidPermalink, err)
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return "", fmt.Errorf("%w: unexpected scheme %q", ErrInvalidPermalink, u.Scheme)
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) < 2 || len(segments) > 3 || segments[0] != "archives" {
+		return "", fmt.Errorf("%w: unexpected path %q", ErrInvalidPermalink, u.Path)
+	}
+	channelID := segments[1]
+	if !channelIDPattern.MatchString(channelID) {
+		return "", fmt.Errorf("%w: unexpected channel ID %q", ErrInvalidPermalink, channelID)
+	}
+	if len(segments) == 3 && !messageTSPattern.MatchString(segments[2]) {
+		return "", fmt.Errorf("%w: unexpected message %q", ErrInvalidPermalink, segments[2])
+	}
+	return channelID, nil
+}
+
+var (
+	channelIDPattern = regexp.MustCompile(`^[CGD][A-Z0-9]+$`)
+	messageTSPattern = regexp.MustCompile(`^p[0-9]+$`)
+)
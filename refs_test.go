@@ -0,0 +1,68 @@
+package slackcnr_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolverResolveRefsFromText(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C012345678", "general"),
+		newTestChannel("C023456789", "random"),
+	}, "", nil).Once()
+	r := slackcnr.New(client, slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)))
+
+	refs, err := r.ResolveRefsFromText(context.Background(),
+		"see <#C012345678|general> and <#C023456789>, not <#C099999999|gone>. again <#C012345678|general>, <@U012345678>",
+	)
+	require.NoError(t, err)
+	require.Len(t, refs, 3)
+	require.Equal(t, "general", refs["C012345678"].Name)
+	require.Equal(t, "random", refs["C023456789"].Name)
+	require.Contains(t, refs, "C099999999")
+	require.Nil(t, refs["C099999999"])
+}
+
+func TestResolverLookupByPermalink(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C012345678", "general"),
+	}, "", nil).Once()
+	r := slackcnr.New(client, slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)))
+	ctx := context.Background()
+
+	cases := []struct {
+		name      string
+		permalink string
+		err       error
+	}{
+		{name: "channel", permalink: "https://team.slack.com/archives/C012345678"},
+		{name: "message", permalink: "https://team.slack.com/archives/C012345678/p1700000000000000"},
+		{name: "thread reply", permalink: "https://team.slack.com/archives/C012345678/p1700000000000100?thread_ts=1700000000.000000&cid=C012345678"},
+		{name: "enterprise", permalink: "https://org.enterprise.slack.com/archives/C012345678/p1700000000000000/"},
+		{name: "unknown channel", permalink: "https://team.slack.com/archives/C099999999/p1700000000000000", err: slackcnr.ErrNotFound},
+		{name: "not archives", permalink: "https://team.slack.com/messages/C012345678", err: slackcnr.ErrInvalidPermalink},
+		{name: "bad message", permalink: "https://team.slack.com/archives/C012345678/x123", err: slackcnr.ErrInvalidPermalink},
+		{name: "bad channel id", permalink: "https://team.slack.com/archives/general", err: slackcnr.ErrInvalidPermalink},
+		{name: "not url", permalink: "C012345678", err: slackcnr.ErrInvalidPermalink},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			channel, err := r.LookupByPermalink(ctx, c.permalink)
+			if c.err != nil {
+				require.ErrorIs(t, err, c.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "general", channel.Name)
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package slackcnr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Registry holds resolvers per team, for multi-workspace apps.
+type Registry struct {
+	mu        sync.RWMutex
+	resolvers map[string]*Resolver
+	opts      registryOptions
+}
+
+type RegistryOption func(*registryOptions)
+
+type registryOptions struct {
+	refreshConcurrency int
+}
+
+// WithRefreshConcurrency sets the maximum number of resolvers refreshed concurrently by RefreshAll. default is 1.
+func WithRefreshConcurrency(n int) RegistryOption {
+	return func(o *registryOptions) {
+		o.refreshConcurrency = n
+	}
+}
+
+// NewRegistry creates a new empty registry.
+func NewRegistry(optFns ...RegistryOption) *Registry {
+	opts := registryOptions{
+		refreshConcurrency: 1,
+	}
+	for _, optFn := range optFns {
+		optFn(&opts)
+	}
+	if opts.refreshConcurrency < 1 {
+		opts.refreshConcurrency = 1
+	}
+	return &Registry{
+		resolvers: make(map[string]*Resolver),
+		opts:      opts,
+	}
+}
+
+// Register registers the resolver for the team. an already registered resolver for the team is replaced.
+func (reg *Registry) Register(teamID string, r *Resolver) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.resolvers[teamID] = r
+}
+
+// Unregister removes the resolver for the team.
+func (reg *Registry) Unregister(teamID string) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	delete(reg.resolvers, teamID)
+}
+
+// Get returns the resolver for the team.
+func (reg *Registry) Get(teamID string) (*Resolver, bool) {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	r, ok := reg.resolvers[teamID]
+	return r, ok
+}
+
+// TeamIDs returns the registered team IDs, sorted.
+func (reg *Registry) TeamIDs() []string {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	teamIDs := make([]string, 0, len(reg.resolvers))
+	for teamID := range reg.resolvers {
+		teamIDs = append(teamIDs, teamID)
+	}
+	sort.Strings(teamIDs)
+	return teamIDs
+}
+
+// RefreshAll refreshes every registered resolver, up to the concurrency set by WithRefreshConcurrency.
+// errors are aggregated with errors.Join, each annotated with the team ID.
+// when ctx is canceled, no more refreshes are started and in-flight refreshes are canceled.
+func (reg *Registry) RefreshAll(ctx context.Context) error {
+	ctx = ensureContext(ctx)
+	teamIDs := reg.TeamIDs()
+	sem := make(chan struct{}, reg.opts.refreshConcurrency)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var errs []error
+	appendErr := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, err)
+	}
+	for _, teamID := range teamIDs {
+		r, ok := reg.Get(teamID)
+		if !ok {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			appendErr(fmt.Errorf("team %s: %w", teamID, err))
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			appendErr(fmt.Errorf("team %s: %w", teamID, ctx.Err()))
+			continue
+		case sem <- struct{}{}:
+		}
+		wg.Add(1)
+		go func(teamID string, r *Resolver) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := r.Refresh(ctx); err != nil {
+				appendErr(fmt.Errorf("team %s: %w", teamID, err))
+			}
+		}(teamID, r)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
@@ -0,0 +1,51 @@
+package slackcnr_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryRefreshAll(t *testing.T) {
+	apiErr := errors.New("api error")
+	registry := slackcnr.NewRegistry(slackcnr.WithRefreshConcurrency(2))
+	for _, teamID := range []string{"T1", "T2", "T3"} {
+		client := &mockSlackClient{t: t}
+		defer client.AssertExpectations(t)
+		if teamID == "T2" {
+			client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{}, "", apiErr).Once()
+		} else {
+			client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+				newTestChannel("C_"+teamID, "general"),
+			}, "", nil).Once()
+		}
+		registry.Register(teamID, slackcnr.New(client, slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0))))
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	err := registry.RefreshAll(ctx)
+	require.ErrorIs(t, err, apiErr)
+	require.ErrorContains(t, err, "team T2")
+	require.NotContains(t, err.Error(), "team T1")
+
+	r, ok := registry.Get("T3")
+	require.True(t, ok)
+	channel, err := r.Lookup(ctx, "general")
+	require.NoError(t, err)
+	require.Equal(t, "C_T3", channel.ID)
+}
+
+func TestRegistryRefreshAll__Canceled(t *testing.T) {
+	registry := slackcnr.NewRegistry()
+	registry.Register("T1", slackcnr.New(&mockSlackClient{t: t}))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := registry.RefreshAll(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,92 @@
+package slackcnr
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// WithTrackRenames keeps resolving the previous name of a renamed channel for the retain duration.
+// renames are detected by comparing the channel names between refreshes.
+func WithTrackRenames(retain time.Duration) ResolverOption {
+	return func(o *resolverOptions) {
+		o.trackRenames = true
+		o.renameRetention = retain
+	}
+}
+
+type renameAlias struct {
+	channelName string
+	expiresAt   time.Time
+}
+
+type renameTracker struct {
+	mu      sync.Mutex
+	names   map[string]string
+	aliases map[string]renameAlias
+}
+
+// observe records the channel names, and registers the previous name as an alias if the channel was renamed.
+func (t *renameTracker) observe(channels []slack.Channel, retain time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.names == nil {
+		t.names = make(map[string]string)
+		t.aliases = make(map[string]renameAlias)
+	}
+	now := time.Now()
+	for _, channel := range channels {
+		prev, ok := t.names[channel.ID]
+		t.names[channel.ID] = channel.Name
+		delete(t.aliases, channel.Name)
+		if !ok || prev == channel.Name {
+			continue
+		}
+		t.aliases[prev] = renameAlias{
+			channelName: channel.Name,
+			expiresAt:   now.Add(retain),
+		}
+	}
+	for name, alias := range t.aliases {
+		if now.After(alias.expiresAt) {
+			delete(t.aliases, name)
+		}
+	}
+}
+
+// reset forgets the observed names and aliases.
+func (t *renameTracker) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.names = nil
+	t.aliases = nil
+}
+
+// resolve returns the current name for the previous name, if it is within the retention.
+func (t *renameTracker) resolve(channelName string) (string, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	alias, ok := t.aliases[channelName]
+	if !ok {
+		return "", false
+	}
+	if time.Now().After(alias.expiresAt) {
+		delete(t.aliases, channelName)
+		return "", false
+	}
+	return alias.channelName, true
+}
+
+func (r *Resolver) lookupRenamed(ctx context.Context, channelName string, err error) (*slack.Channel, error) {
+	if !r.opts.trackRenames || !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	current, ok := r.renames.resolve(channelName)
+	if !ok {
+		return nil, err
+	}
+	return r.getByChannelName(ctx, current)
+}
@@ -0,0 +1,43 @@
+package slackcnr_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolverLookup__TrackRenames(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C012345678", "old-name"),
+	}, "", nil).Once()
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C012345678", "new-name"),
+	}, "", nil).Once()
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+		slackcnr.WithTrackRenames(200*time.Millisecond),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	require.NoError(t, r.Refresh(ctx))
+	require.NoError(t, r.Refresh(ctx))
+
+	channel, err := r.Lookup(ctx, "new-name")
+	require.NoError(t, err)
+	require.Equal(t, "C012345678", channel.ID)
+	channel, err = r.Lookup(ctx, "old-name")
+	require.NoError(t, err)
+	require.Equal(t, "C012345678", channel.ID)
+	require.Equal(t, "new-name", channel.Name)
+
+	time.Sleep(300 * time.Millisecond)
+	_, err = r.Lookup(ctx, "old-name")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound)
+}
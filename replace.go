@@ -0,0 +1,96 @@
+package slackcnr
+
+import (
+	"context"
+	"errors"
+
+	"github.com/slack-go/slack"
+)
+
+// ReplaceableStorage is an optional interface for storages that can replace all cached channels at once.
+type ReplaceableStorage interface {
+	Storage
+	// ReplaceChannels replaces all cached channels with the given ones, and resets the expiry like SetChannels.
+	// readers must see either the previous or the new channels, never a mix of both.
+	ReplaceChannels(ctx context.Context, channels []slack.Channel) error
+}
+
+var _ ReplaceableStorage = (*InMemoryStorage)(nil)
+
+// WithAtomicReplace makes a full refresh replace the cached channels at once, instead of adding them to the cache,
+// so that channels deleted on Slack are dropped and readers never observe a half written cache.
+// a refresh that does not see all channels, such as one with WithIncrementalSync
+// or one skipping a pass by WithTolerateMissingPublicScope, adds the channels as usual.
+// it is mutually exclusive with WithWriteOnlyChanged, and with New, a replacing refresh writes all channels regardless.
+// it requires the cache storage to implement ReplaceableStorage, NewChecked returns an error otherwise.
+func WithAtomicReplace() ResolverOption {
+	return func(o *resolverOptions) {
+		o.atomicReplace = true
+	}
+}
+
+func (o *resolverOptions) validateAtomicReplace() error {
+	if !o.atomicReplace {
+		return nil
+	}
+	if o.writeOnlyChanged {
+		return errors.New("WithAtomicReplace and WithWriteOnlyChanged are mutually exclusive")
+	}
+	if _, ok := o.cacheStorage.(ReplaceableStorage); !ok {
+		return errors.New("cache storage does not support replace")
+	}
+	return nil
+}
+
+// ReplaceChannels replaces all cached channels as a new epoch.
+// the new index is built aside without the lock, and swapped in under the write lock,
+// so readers see either the previous or the new index, and are blocked only for the swap.
+// channels added by AddChannels while the new index is built are dropped by the swap.
+func (s *InMemoryStorage) ReplaceChannels(ctx context.Context, channels []slack.Channel) error {
+	next := NewInMemoryStorage(0)
+	s.mu.RLock()
+	next.prefer = s.prefer
+	next.keyFunc = s.keyFunc
+	next.maxEntries = s.maxEntries
+	next.eviction = s.eviction
+	if s.ages != nil {
+		// the new epoch keeps the ages of the channels, so that the channels looked up are not evicted by the replace,
+		// and continues the counter, so that lookups after the swap count as more recent.
+		next.ages = make(map[string]*entryAge, len(s.ages))
+		for id, age := range s.ages {
+			kept := &entryAge{stored: age.stored}
+			kept.used.Store(age.used.Load())
+			next.ages[id] = kept
+		}
+		next.seq.Store(s.seq.Load())
+	}
+	s.mu.RUnlock()
+	next.set(channels)
+	for id := range next.ages {
+		if _, ok := next.channels[id]; !ok {
+			delete(next.ages, id)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.channels = next.channels
+	s.namesById = next.namesById
+	s.keysById = next.keysById
+	s.idsByName = next.idsByName
+	if s.ages != nil {
+		s.ages = next.ages
+		s.seq.Store(max(s.seq.Load(), next.seq.Load()))
+	}
+	s.epoch++
+	s.lastSetTime = now()
+	return nil
+}
+
+// Epoch returns the number of times the channels were replaced by ReplaceChannels.
+func (s *InMemoryStorage) Epoch() uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.epoch
+}
@@ -0,0 +1,117 @@
+package slackcnr_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryStorage__ReplaceChannelsConcurrentReads(t *testing.T) {
+	ctx := context.Background()
+	epochs := make([][]slack.Channel, 2)
+	for e := range epochs {
+		for i := 0; i < 100; i++ {
+			epochs[e] = append(epochs[e], newTestChannel(fmt.Sprintf("C%d%08d", e, i), fmt.Sprintf("channel-%02d", i)))
+		}
+	}
+	storage := slackcnr.NewInMemoryStorage(0)
+	require.NoError(t, storage.ReplaceChannels(ctx, epochs[0]))
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				channels, err := storage.List(ctx)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				prefix := channels[0].ID[:2]
+				for _, channel := range channels {
+					if len(channels) != 100 || channel.ID[:2] != prefix {
+						t.Errorf("observed a partially replaced index: %d channels, %s and %s", len(channels), prefix, channel.ID)
+						return
+					}
+				}
+				channel, err := storage.GetByChannelName(ctx, "channel-42")
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if channel.ID[2:] != "00000042" {
+					t.Errorf("unexpected channel %s", channel.ID)
+					return
+				}
+			}
+		}()
+	}
+	for i := 1; i <= 50; i++ {
+		require.NoError(t, storage.ReplaceChannels(ctx, epochs[i%2]))
+	}
+	close(done)
+	wg.Wait()
+	require.EqualValues(t, 51, storage.Epoch())
+}
+
+func TestResolverRefresh__AtomicReplace(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C012345678", "general"),
+		newTestChannel("C023456789", "deleted-soon"),
+	}, "", nil).Once()
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C012345678", "general"),
+	}, "", nil).Once()
+	storage := slackcnr.NewInMemoryStorage(0)
+	r, err := slackcnr.NewChecked(client,
+		slackcnr.WithCacheStorage(storage),
+		slackcnr.WithAtomicReplace(),
+	)
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, r.Refresh(ctx))
+	_, err = r.RefreshAndLookup(ctx, "general")
+	require.NoError(t, err)
+	_, err = r.Lookup(ctx, "deleted-soon")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound, "a channel no longer returned is dropped")
+	require.EqualValues(t, 2, storage.Epoch())
+
+	_, err = slackcnr.NewChecked(client,
+		slackcnr.WithCacheStorage(storage),
+		slackcnr.WithAtomicReplace(),
+		slackcnr.WithWriteOnlyChanged(),
+	)
+	require.Error(t, err)
+}
+
+func TestResolverRefresh__AtomicReplaceWithWriteOnlyChanged(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C012345678", "general"),
+	}, "", nil).Twice()
+	r := slackcnr.New(client,
+		slackcnr.WithAtomicReplace(),
+		slackcnr.WithWriteOnlyChanged(),
+	)
+	ctx := context.Background()
+	require.NoError(t, r.Refresh(ctx))
+	channel, err := r.RefreshAndLookup(ctx, "general")
+	require.NoError(t, err, "an unchanged channel is not dropped by the replace")
+	require.Equal(t, "C012345678", channel.ID)
+}
@@ -3,7 +3,10 @@ package slackcnr
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log/slog"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/slack-go/slack"
@@ -16,29 +19,176 @@ type SlackClient interface {
 
 var _ SlackClient = (*slack.Client)(nil)
 
+// ConversationInfoClient is an optional interface for SlackClient, to fetch a single channel with conversations.info API.
+type ConversationInfoClient interface {
+	SlackClient
+	GetConversationInfoContext(ctx context.Context, input *slack.GetConversationInfoInput) (*slack.Channel, error)
+}
+
+var _ ConversationInfoClient = (*slack.Client)(nil)
+
+// Resolver resolves slack channels by name, with a cache storage refreshed from the Slack API.
+//
+// locking model: lookups never take the resolver lock while the cache is fresh,
+// they only read the cache storage, which synchronizes itself (InMemoryStorage with its own RWMutex).
+// mu coordinates writers: Refresh, RefreshUser, RefreshPublic and SetStorage hold it exclusively,
+// so that refreshes never run concurrently. a lookup finding a stale cache during a refresh waits for it
+// by taking mu shared, so that all waiting lookups proceed at once when the refresh completes.
+// incremental writes such as UpdateChannel do not take mu, and rely on the storage alone.
+// the other states have their own locks or are atomic, and are never held while calling the Slack API.
 type Resolver struct {
 	client SlackClient
 	opts   resolverOptions
-	mu     sync.Mutex
+	mu     sync.RWMutex
+	stats  resolverStats
+
+	subscribers subscribers
+	renames     renameTracker
+	health      refreshHealth
+	changes     changeDetector
+	differ      snapshotDiffer
+
+	// apiCalls is the number of conversations API calls in the current refresh.
+	apiCalls atomic.Int64
+	// loadedChannels is the number of channels fetched in the current refresh, and truncated reports whether
+	// the current refresh stopped early, by WithMaxChannels.
+	loadedChannels atomic.Int64
+	truncated      atomic.Bool
+	// lastRefreshed is the time the last successful refresh completed, guarded by mu.
+	lastRefreshed time.Time
+	// refreshing reports whether a refresh is in flight, readable without mu.
+	refreshing atomic.Bool
+	// teamID is the team of the token at the last refresh, guarded by mu. it is set by WithWorkspaceGuard.
+	teamID string
+	// pinned is the snapshot storage set by Pin.
+	pinned atomic.Pointer[InMemoryStorage]
+	// checkpoint flushes pages during a refresh, set by WithRefreshCheckpointEvery. nil outside of a refresh.
+	checkpoint *refreshCheckpoint
+	// revalidating reports whether a background refresh started by the lookup policy is running.
+	revalidating atomic.Bool
+	// storage is the current cache storage, initially the one set by WithCacheStorage and replaced by SetStorage.
+	storage atomic.Pointer[storageRef]
+	// auto is the background refresh run by Start.
+	auto autoRefresher
+	// sources is the source each cached channel was found by, set by WithRecordSource.
+	sources channelSources
+	// allowed is the normalized allowlist set by WithAllowlist, nil if lookups are not restricted.
+	allowed map[string]struct{}
 }
 
 type ResolverOption func(*resolverOptions)
 
 type resolverOptions struct {
-	searchpublicChannels bool
-	cacheStorage         Storage
-	batchSize            int
-	excludeArchived      bool
-	refreshOnCacheMiss   bool
+	searchpublicChannels     bool
+	userConversationsOnly    bool
+	publicChannelsOnly       bool
+	withoutUserConversations bool
+	cacheStorage             Storage
+	// sharedStorage is set by CloneSharingStorage, the storage-side options are left to the resolver sharing the storage.
+	sharedStorage            bool
+	batchSize                int
+	excludeArchived          bool
+	refreshOnCacheMiss       bool
+	expvarName               string
+	minRefreshDeadline       time.Duration
+	maxAPICalls              int
+	trackRenames             bool
+	renameRetention          time.Duration
+	minRefreshInterval       time.Duration
+	unicodeNormalization     bool
+	healthStaleness          time.Duration
+	incrementalSync          bool
+	logger                   *slog.Logger
+	tolerateMissingScope     bool
+	channelPriority          func(a, b *slack.Channel) bool
+	metricsObserver          MetricsObserver
+	keyFunc                  func(channel *slack.Channel) []string
+	adminEnumeration         bool
+	adminTeamIDs             []string
+	writeOnlyChanged         bool
+	channelEqual             func(a, b *slack.Channel) bool
+	tokenKind                TokenKind
+	channelTypes             []string
+	indexSharedPrefixes      bool
+	memberOnly               bool
+	backfillOnIDLookup       bool
+	workspaceGuard           bool
+	perCallTimeout           time.Duration
+	snapshotStore            SnapshotStore
+	includeArchived          bool
+	archivedOnly             bool
+	refreshWorkers           int
+	checkpointEvery          int
+	teamDomain               string
+	requireNonEmpty          bool
+	lookupPolicy             LookupPolicy
+	autoRefreshInterval      time.Duration
+	refreshTimeout           time.Duration
+	propagateDeadline        bool
+	refreshOnAnyError        bool
+	newTicker                func(interval time.Duration) (<-chan time.Time, func())
+	includeLocale            bool
+	nameNormalizers          []func(string) string
+	apiURL                   string
+	separatorInsensitive     bool
+	lookupConcurrency        int
+	recordSource             bool
+	allowlist                map[string]struct{}
+	maxEntries               int
+	evictionPolicy           EvictionPolicy
+	atomicReplace            bool
+	skipPublicIfUserComplete bool
+	maxChannels              int
 }
 
 // WithSearchPublicChannels enables searching public channels. with conversations.list API.
+// public channels are searched in addition to the channels the bot is a member of.
+// requires channels:read scope, and the scopes of WithUserConversationsOnly.
 func WithSearchPublicChannels() ResolverOption {
 	return func(o *resolverOptions) {
 		o.searchpublicChannels = true
 	}
 }
 
+// WithUserConversationsOnly searches only the channels the bot is a member of. with users.conversations API.
+// this is the default behavior.
+// requires channels:read, groups:read, im:read and mpim:read scopes, according to the conversation types.
+// it can not be combined with WithSearchPublicChannels or WithPublicChannelsOnly.
+func WithUserConversationsOnly() ResolverOption {
+	return func(o *resolverOptions) {
+		o.userConversationsOnly = true
+	}
+}
+
+// WithPublicChannelsOnly searches only public channels. with conversations.list API.
+// the users.conversations API is not called, so the bot does not need to be a member of the channels.
+// requires channels:read scope.
+// it can not be combined with WithUserConversationsOnly.
+func WithPublicChannelsOnly() ResolverOption {
+	return func(o *resolverOptions) {
+		o.publicChannelsOnly = true
+	}
+}
+
+// WithoutUserConversations skips the users.conversations API pass, so the bot does not need to be a member of the channels.
+// the public channels pass must be enabled with WithSearchPublicChannels, NewChecked returns an error otherwise.
+// WithoutUserConversations with WithSearchPublicChannels is equivalent to WithPublicChannelsOnly.
+func WithoutUserConversations() ResolverOption {
+	return func(o *resolverOptions) {
+		o.withoutUserConversations = true
+	}
+}
+
+// WithSkipPublicIfUserComplete skips the public channels pass of a refresh, when the users.conversations pass fits in a single page.
+// it is an optimization for a specific deployment shape: a small workspace where the bot is a member of all channels,
+// so the public channels pass would find nothing new. public channels the bot is not a member of are missed otherwise.
+// it has no effect with WithIncrementalSync, or with WithRefreshWorkers above 1 since the passes run concurrently.
+func WithSkipPublicIfUserComplete() ResolverOption {
+	return func(o *resolverOptions) {
+		o.skipPublicIfUserComplete = true
+	}
+}
+
 // WithCacheStorage sets the cache storage for the resolver. default is in-memory storage.
 func WithCacheStorage(storage Storage) ResolverOption {
 	return func(o *resolverOptions) {
@@ -47,6 +197,7 @@ func WithCacheStorage(storage Storage) ResolverOption {
 }
 
 // WithBatchSize sets the batch size for users.conversations API and conversations.list API limit parameter. default is 1000.
+// valid range is 1 to 1000. New clamps out of range values, NewChecked returns an error.
 func WithBatchSize(size int) ResolverOption {
 	return func(o *resolverOptions) {
 		o.batchSize = size
@@ -54,12 +205,52 @@ func WithBatchSize(size int) ResolverOption {
 }
 
 // WithExcludeArchived excludes archived channels from the search result.
+// it is mutually exclusive with WithIncludeArchived.
 func WithExcludeArchived() ResolverOption {
 	return func(o *resolverOptions) {
 		o.excludeArchived = true
 	}
 }
 
+// WithIncludeArchived ensures archived channels are indexed, so that Lookup returns them with IsArchived set.
+// it is the default, but makes the intent explicit.
+// it is mutually exclusive with WithExcludeArchived. New lets WithIncludeArchived take precedence, NewChecked returns an error.
+func WithIncludeArchived() ResolverOption {
+	return func(o *resolverOptions) {
+		o.includeArchived = true
+	}
+}
+
+// WithArchivedOnly indexes only the archived channels on refresh, the opposite of WithExcludeArchived,
+// such as for cleanup or audit tooling. lookups for channels not archived return ErrNotFound.
+// it is mutually exclusive with WithExcludeArchived. New lets WithArchivedOnly take precedence, NewChecked returns an error.
+func WithArchivedOnly() ResolverOption {
+	return func(o *resolverOptions) {
+		o.archivedOnly = true
+	}
+}
+
+// WithMemberOnly indexes only the channels the token owner is a member of (is_member is true) on refresh.
+// it is useful with WithSearchPublicChannels, when only the channels the bot can post to matter.
+// lookups for other channels return ErrNotFound.
+func WithMemberOnly() ResolverOption {
+	return func(o *resolverOptions) {
+		o.memberOnly = true
+	}
+}
+
+// ErrEmptyWorkspace is returned by Refresh with WithRequireNonEmptyCache, when the refresh found no channel.
+var ErrEmptyWorkspace = errors.New("refresh found no channel, check the token and its scopes")
+
+// WithRequireNonEmptyCache makes a refresh that finds no channel fail with ErrEmptyWorkspace, without writing to the cache storage.
+// an empty result almost always means a token or scope problem, such as a bot that is not invited to any channel.
+// do not use it for legitimately empty workspaces. it is ignored with WithIncrementalSync, since a refresh may find no new channel.
+func WithRequireNonEmptyCache() ResolverOption {
+	return func(o *resolverOptions) {
+		o.requireNonEmpty = true
+	}
+}
+
 // WithRefreshOnCacheMiss refreshes the cache storage when a channel is not found in the cache.
 func WithRefreshOnCacheMiss() ResolverOption {
 	return func(o *resolverOptions) {
@@ -67,125 +258,960 @@ func WithRefreshOnCacheMiss() ResolverOption {
 	}
 }
 
+// WithRefreshOnAnyError refreshes the cache storage when reading a channel from the cache fails for any reason,
+// not only when it is not found like WithRefreshOnCacheMiss, which it implies.
+// it is useful for storage backends with transient read errors. the read after the refresh is not retried again.
+func WithRefreshOnAnyError() ResolverOption {
+	return func(o *resolverOptions) {
+		o.refreshOnAnyError = true
+	}
+}
+
+const (
+	minBatchSize     = 1
+	maxBatchSize     = 1000
+	defaultBatchSize = 1000
+)
+
+// WithSkipRefreshWhenInsufficientDeadline serves the stale cache instead of refreshing,
+// when the remaining time of the context deadline is less than minRemaining.
+func WithSkipRefreshWhenInsufficientDeadline(minRemaining time.Duration) ResolverOption {
+	return func(o *resolverOptions) {
+		o.minRefreshDeadline = minRemaining
+	}
+}
+
+// WithMaxAPICallsPerRefresh caps the total number of users.conversations and conversations.list API calls in a single refresh.
+// retries on rate limit are also counted. if the cap is exceeded, the refresh fails with ErrMaxAPICallsExceeded.
+// default is 0, no limit.
+func WithMaxAPICallsPerRefresh(n int) ResolverOption {
+	return func(o *resolverOptions) {
+		o.maxAPICalls = n
+	}
+}
+
+// WithMaxChannels caps the number of channels fetched in a single refresh, as a safety net for enormous workspaces.
+// once n channels are fetched, the pagination stops early with a warning, and the refresh succeeds with the truncated result.
+// the channels beyond the cap are never cached, and lookups for them return ErrNotFound, so combine it with filters
+// applied by the API, such as WithExcludeArchived or WithChannelTypes, to keep the channels that matter.
+// the channels are counted before WithMemberOnly and WithArchivedOnly are applied.
+// a truncated refresh does not see all channels, so channels no longer on Slack are not evicted nor replaced.
+// default is 0, no limit.
+func WithMaxChannels(n int) ResolverOption {
+	return func(o *resolverOptions) {
+		o.maxChannels = n
+	}
+}
+
+// WithMinRefreshInterval skips a refresh if the previous refresh completed within the interval.
+// regardless of this option, a refresh that waited for another in-flight refresh is skipped.
+func WithMinRefreshInterval(d time.Duration) ResolverOption {
+	return func(o *resolverOptions) {
+		o.minRefreshInterval = d
+	}
+}
+
 func defaultOptions() resolverOptions {
 	return resolverOptions{
-		batchSize:    1000,
+		batchSize:    defaultBatchSize,
 		cacheStorage: NewInMemoryStorage(24 * time.Hour),
 	}
 }
 
+func (o *resolverOptions) validate() error {
+	if o.batchSize < minBatchSize || o.batchSize > maxBatchSize {
+		return fmt.Errorf("batch size must be between %d and %d, got %d", minBatchSize, maxBatchSize, o.batchSize)
+	}
+	if o.cacheStorage == nil {
+		return errors.New("cache storage is nil")
+	}
+	if o.channelPriority != nil {
+		if _, ok := o.cacheStorage.(ChannelPriorityStorage); !ok {
+			return errors.New("cache storage does not support channel priority")
+		}
+	}
+	if err := o.validateCheckpoint(); err != nil {
+		return err
+	}
+	if err := o.validateChannelTypes(); err != nil {
+		return err
+	}
+	if err := o.validateMaxEntries(); err != nil {
+		return err
+	}
+	if err := o.validateAtomicReplace(); err != nil {
+		return err
+	}
+	if o.workspaceGuard {
+		if _, ok := o.cacheStorage.(ResettableStorage); !ok {
+			return errors.New("cache storage does not support reset")
+		}
+	}
+	if o.keyFunc != nil || o.indexSharedPrefixes || o.separatorInsensitive {
+		if _, ok := o.cacheStorage.(KeyedStorage); !ok {
+			return errors.New("cache storage does not support key func")
+		}
+	}
+	if o.includeArchived && o.excludeArchived {
+		return errors.New("WithIncludeArchived and WithExcludeArchived are mutually exclusive")
+	}
+	if o.archivedOnly && o.excludeArchived {
+		return errors.New("WithArchivedOnly and WithExcludeArchived are mutually exclusive")
+	}
+	if o.userConversationsOnly && o.publicChannelsOnly {
+		return errors.New("WithUserConversationsOnly and WithPublicChannelsOnly are mutually exclusive")
+	}
+	if o.userConversationsOnly && o.searchpublicChannels {
+		return errors.New("WithUserConversationsOnly and WithSearchPublicChannels are mutually exclusive")
+	}
+	if o.userConversationsOnly && o.withoutUserConversations {
+		return errors.New("WithUserConversationsOnly and WithoutUserConversations are mutually exclusive")
+	}
+	if !o.useUserConversations() && !o.usePublicChannels() {
+		return errors.New("no refresh source is enabled, WithoutUserConversations requires WithSearchPublicChannels")
+	}
+	return nil
+}
+
+// useUserConversations reports whether the refresh calls users.conversations API.
+func (o *resolverOptions) useUserConversations() bool {
+	if o.userConversationsOnly {
+		return true
+	}
+	return !o.publicChannelsOnly && !o.withoutUserConversations
+}
+
+// usePublicChannels reports whether the refresh calls conversations.list API.
+// on conflicting options, WithUserConversationsOnly takes precedence.
+func (o *resolverOptions) usePublicChannels() bool {
+	if o.userConversationsOnly {
+		return false
+	}
+	return o.searchpublicChannels || o.publicChannelsOnly
+}
+
+func clampBatchSize(size int) int {
+	if size < minBatchSize {
+		return minBatchSize
+	}
+	if size > maxBatchSize {
+		return maxBatchSize
+	}
+	return size
+}
+
 // New creates a new resolver with the provided slack client and options.
+// out of range batch size is clamped into the valid range.
+// on conflicting refresh source options, WithUserConversationsOnly takes precedence.
+// on conflicting archived options, WithIncludeArchived and WithArchivedOnly take precedence.
 func New(client SlackClient, optFns ...ResolverOption) *Resolver {
 	opts := defaultOptions()
 	for _, optFn := range optFns {
 		optFn(&opts)
 	}
-	return &Resolver{
+	return newResolver(client, opts)
+}
+
+// newResolver creates a resolver with the options resolved like New.
+func newResolver(client SlackClient, opts resolverOptions) *Resolver {
+	opts.batchSize = clampBatchSize(opts.batchSize)
+	if opts.includeArchived || opts.archivedOnly {
+		opts.excludeArchived = false
+	}
+	if opts.cacheStorage == nil {
+		opts.cacheStorage = defaultOptions().cacheStorage
+	}
+	r := &Resolver{
 		client: client,
 		opts:   opts,
 	}
+	r.init()
+	if err := r.publishExpvar(); err != nil {
+		r.logger(context.Background()).Warn("failed to publish stats to expvar", "error", err)
+	}
+	if err := opts.validateChannelTypes(); err != nil {
+		r.logger(context.Background()).Warn("invalid channel types, no channel may be fetched", "error", err)
+	}
+	return r
+}
+
+func (r *Resolver) init() {
+	if !r.opts.sharedStorage {
+		r.configureStorage(r.opts.cacheStorage)
+	}
+	r.storage.Store(&storageRef{Storage: r.opts.cacheStorage})
+	r.allowed = r.opts.allowedNames()
+}
+
+// configureStorage applies the storage-side options to the storage.
+func (r *Resolver) configureStorage(storage Storage) {
+	if r.opts.channelPriority != nil {
+		if s, ok := storage.(ChannelPriorityStorage); ok {
+			s.SetChannelPriority(r.opts.channelPriority)
+		}
+	}
+	if r.opts.maxEntries > 0 {
+		if s, ok := storage.(EvictingStorage); ok {
+			s.SetMaxEntries(r.opts.maxEntries, r.opts.evictionPolicy)
+		}
+	}
+	if keyFunc := r.opts.lookupKeyFunc(); keyFunc != nil {
+		if s, ok := storage.(KeyedStorage); ok {
+			s.SetKeyFunc(keyFunc)
+		}
+	}
+}
+
+// NewChecked creates a new resolver like New, but returns an error if the configuration is invalid.
+func NewChecked(client SlackClient, optFns ...ResolverOption) (*Resolver, error) {
+	if client == nil {
+		return nil, errors.New("slack client is nil")
+	}
+	opts := defaultOptions()
+	for _, optFn := range optFns {
+		optFn(&opts)
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	if _, ok := client.(AdminConversationsClient); opts.adminEnumeration && !ok {
+		return nil, ErrAdminNotSupported
+	}
+	if _, ok := client.(AuthTestClient); opts.workspaceGuard && !ok {
+		return nil, ErrAuthTestNotSupported
+	}
+	r := &Resolver{
+		client: client,
+		opts:   opts,
+	}
+	// the expvar name is checked before init, so that the storage is left untouched on error.
+	if err := r.publishExpvar(); err != nil {
+		return nil, err
+	}
+	r.init()
+	return r, nil
+}
+
+// Client returns the slack client the resolver was constructed with.
+// it is useful to make additional calls after resolving a channel. the client can not be replaced.
+func (r *Resolver) Client() SlackClient {
+	return r.client
 }
 
 // Lookup finds a channel by name.
+// all methods of Resolver treat a nil context as context.Background().
 func (r *Resolver) Lookup(ctx context.Context, channelName string) (*slack.Channel, error) {
+	ctx = ensureContext(ctx)
+	channelName = r.opts.normalizeName(channelName)
+	if !r.allows(channelName) {
+		r.stats.misses.Add(1)
+		r.logger(ctx).DebugContext(ctx, "channel not in allowlist", "channel_name", safeName(channelName))
+		return nil, ErrNotFound
+	}
 	if err := r.prepare(ctx); err != nil {
 		return nil, err
 	}
-	channel, err := r.opts.cacheStorage.GetByChannelName(ctx, channelName)
+	channel, err := r.get(ctx, channelName)
+	if err == nil {
+		r.stats.hits.Add(1)
+	}
+	if errors.Is(err, ErrNotFound) {
+		r.stats.misses.Add(1)
+		r.logger(ctx).DebugContext(ctx, "channel not found in cache", "channel_name", safeName(channelName))
+	}
 	if err != nil {
-		if !r.opts.refreshOnCacheMiss {
+		if !r.opts.refreshOnCacheMiss && !r.opts.refreshOnAnyError {
 			return nil, err
 		}
 		if !errors.Is(err, ErrNotFound) {
-			return nil, err
+			if !r.opts.refreshOnAnyError {
+				return nil, err
+			}
+			r.logger(ctx).WarnContext(ctx, "failed to read cache, refreshing", "channel_name", safeName(channelName), "error", err)
 		}
 		if err := r.Refresh(ctx); err != nil {
 			return nil, err
 		}
-		channel, err = r.opts.cacheStorage.GetByChannelName(ctx, channelName)
+		channel, err = r.get(ctx, channelName)
 	}
 	return channel, err
 }
 
+// ensureContext replaces a nil context with context.Background(), to avoid confusing panics deep inside slack-go.
+func ensureContext(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.Background()
+	}
+	return ctx
+}
+
+func (r *Resolver) get(ctx context.Context, channelName string) (*slack.Channel, error) {
+	if !r.allows(channelName) {
+		return nil, ErrNotFound
+	}
+	channel, err := r.getUnrestricted(ctx, channelName)
+	if err != nil {
+		return nil, err
+	}
+	if !r.allowsChannel(channel) {
+		return nil, ErrNotFound
+	}
+	return channel, nil
+}
+
+func (r *Resolver) getUnrestricted(ctx context.Context, channelName string) (*slack.Channel, error) {
+	channel, err := r.getByChannelName(ctx, channelName)
+	if err != nil && r.opts.indexSharedPrefixes {
+		channel, err = r.lookupSharedPrefixed(ctx, channelName, err)
+	}
+	if err != nil && r.opts.separatorInsensitive {
+		channel, err = r.lookupSeparatorInsensitive(ctx, channelName, err)
+	}
+	if err != nil {
+		return r.lookupRenamed(ctx, channelName, err)
+	}
+	return channel, nil
+}
+
+// MustLookup is like Lookup but panics if the channel can not be resolved.
+// it is intended for program initialization, such as resolving well-known channels on config loading,
+// not for request handling.
+func (r *Resolver) MustLookup(ctx context.Context, channelName string) *slack.Channel {
+	channel, err := r.Lookup(ctx, channelName)
+	if err != nil {
+		panic(fmt.Sprintf("slackcnr: lookup channel `%s`: %v", channelName, err))
+	}
+	return channel
+}
+
+// LookupBestEffort is like Lookup, but returns nil instead of an error, whether the channel is not found or the lookup failed.
+// it is meant for non-critical paths such as fire-and-forget logging, where the error would be ignored anyway.
+// failures other than a miss are logged with the logger of the resolver.
+func (r *Resolver) LookupBestEffort(ctx context.Context, channelName string) *slack.Channel {
+	ctx = ensureContext(ctx)
+	channel, err := r.Lookup(ctx, channelName)
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			r.logger(ctx).WarnContext(ctx, "best effort lookup failed", "channel_name", safeName(channelName), "error", err)
+		}
+		return nil
+	}
+	return channel
+}
+
+// ErrNotMember is returned by LookupForPost when the token owner is not a member of the channel.
+var ErrNotMember = errors.New("not a member of the channel")
+
+// LookupForPost is like Lookup, but returns ErrNotMember along with the channel when is_member is false,
+// so that the caller can join the channel before posting, instead of failing later with a confusing error.
+// direct messages have no membership and are returned as is.
+func (r *Resolver) LookupForPost(ctx context.Context, channelName string) (*slack.Channel, error) {
+	channel, err := r.Lookup(ctx, channelName)
+	if err != nil {
+		return nil, err
+	}
+	if !channel.IsMember && !channel.IsIM {
+		return channel, fmt.Errorf("%w: %s", ErrNotMember, channel.ID)
+	}
+	return channel, nil
+}
+
+// Find is like Lookup, but reports a channel that can not be found with ok=false instead of ErrNotFound.
+// the error is reserved for real failures, such as a failed refresh.
+func (r *Resolver) Find(ctx context.Context, channelName string) (channel *slack.Channel, ok bool, err error) {
+	channel, err = r.Lookup(ctx, channelName)
+	if errors.Is(err, ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return channel, true, nil
+}
+
+// LookupLite finds a channel by name, and returns only its ID and whether it is archived.
+func (r *Resolver) LookupLite(ctx context.Context, channelName string) (id string, archived bool, err error) {
+	channel, err := r.Lookup(ctx, channelName)
+	if err != nil {
+		return "", false, err
+	}
+	return channel.ID, channel.IsArchived, nil
+}
+
+// LookupTopic finds a channel by name, and returns its topic.
+// users.conversations and conversations.list APIs include the topic, so no additional API call is made.
+func (r *Resolver) LookupTopic(ctx context.Context, channelName string) (string, error) {
+	channel, err := r.Lookup(ctx, channelName)
+	if err != nil {
+		return "", err
+	}
+	return channel.Topic.Value, nil
+}
+
+// LookupPurpose finds a channel by name, and returns its purpose.
+// users.conversations and conversations.list APIs include the purpose, so no additional API call is made.
+func (r *Resolver) LookupPurpose(ctx context.Context, channelName string) (string, error) {
+	channel, err := r.Lookup(ctx, channelName)
+	if err != nil {
+		return "", err
+	}
+	return channel.Purpose.Value, nil
+}
+
+// WithIncludeLocale requests the locale of channels fetched with conversations.info API, by FetchAndCacheByID and LookupByID.
+// users.conversations and conversations.list APIs do not return the locale, so channels cached by a refresh have none.
+func WithIncludeLocale() ResolverOption {
+	return func(o *resolverOptions) {
+		o.includeLocale = true
+	}
+}
+
+// LookupLocale finds a channel by name, and returns its locale, empty if unknown. see WithIncludeLocale.
+func (r *Resolver) LookupLocale(ctx context.Context, channelName string) (string, error) {
+	channel, err := r.Lookup(ctx, channelName)
+	if err != nil {
+		return "", err
+	}
+	return channel.Locale, nil
+}
+
+// ErrConversationInfoNotSupported is returned when the slack client does not implement ConversationInfoClient.
+var ErrConversationInfoNotSupported = errors.New("slack client does not support conversations.info")
+
+// FetchAndCacheByID fetches a single channel by ID with conversations.info API, and stores it in the cache storage.
+// it is the cheapest way to fill the cache for a known channel ID, without a full refresh.
+// while pinned by Pin, it calls no API and returns ErrNotFound.
+func (r *Resolver) FetchAndCacheByID(ctx context.Context, channelID string) (*slack.Channel, error) {
+	ctx = ensureContext(ctx)
+	if r.isPinned() {
+		return nil, ErrNotFound
+	}
+	client, ok := r.client.(ConversationInfoClient)
+	if !ok {
+		return nil, ErrConversationInfoNotSupported
+	}
+	callCtx, cancel := r.callContext(ctx)
+	defer cancel()
+	channel, err := client.GetConversationInfoContext(callCtx, &slack.GetConversationInfoInput{
+		ChannelID:     channelID,
+		IncludeLocale: r.opts.includeLocale,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := r.setChannels(ctx, []slack.Channel{*channel}, false); err != nil {
+		return nil, err
+	}
+	return channel, nil
+}
+
+// ErrSearchNotSupported is returned when the cache storage does not implement SearchableStorage.
+var ErrSearchNotSupported = errors.New("cache storage does not support search")
+
+// SearchContains finds channels whose name contains substr, case-insensitively, sorted by name.
+// if limit is greater than 0, at most limit channels are returned.
+func (r *Resolver) SearchContains(ctx context.Context, substr string, limit int) ([]slack.Channel, error) {
+	ctx = ensureContext(ctx)
+	substr = r.opts.normalizeName(substr)
+	searchable, ok := r.readStorage().(SearchableStorage)
+	if !ok {
+		return nil, ErrSearchNotSupported
+	}
+	if err := r.prepare(ctx); err != nil {
+		return nil, err
+	}
+	return searchable.SearchContains(ctx, substr, limit)
+}
+
 func (r *Resolver) prepare(ctx context.Context) error {
-	if !r.opts.cacheStorage.NeedRefresh(ctx) {
+	if r.isPinned() || !r.cacheStorage().NeedRefresh(ctx) {
+		return nil
+	}
+	if r.insufficientDeadline(ctx) {
+		r.stats.degraded.Add(1)
+		return nil
+	}
+	policy := r.opts.lookupPolicy
+	if r.refreshing.Load() {
+		if policy.StaleOK {
+			r.stats.degraded.Add(1)
+			return nil
+		}
+		// a refresh is already underway, wait for it instead of queueing another one, whatever its result.
+		r.mu.RLock()
+		r.mu.RUnlock()
+		return nil
+	}
+	if policy.StaleOK && !policy.PreferFresh && r.populated(ctx) {
+		r.revalidate(ctx)
+		r.stats.degraded.Add(1)
+		return nil
+	}
+	err := r.refreshWithin(ctx, policy.MaxWait)
+	if err != nil && policy.StaleOK {
+		r.logger(ctx).WarnContext(ctx, "serving stale cache", "error", err)
+		r.stats.degraded.Add(1)
 		return nil
 	}
-	return r.Refresh(ctx)
+	return err
+}
+
+// populated reports whether the cache storage has channels to serve stale, from a refresh of this resolver
+// or written by another process. a storage not implementing SizedStorage is assumed populated.
+func (r *Resolver) populated(ctx context.Context) bool {
+	if r.stats.lastRefreshTime.Load() != 0 {
+		return true
+	}
+	s, ok := r.cacheStorage().(SizedStorage)
+	if !ok {
+		return true
+	}
+	size, err := s.CacheSize(ctx)
+	return err != nil || size.Channels > 0
+}
+
+func (r *Resolver) insufficientDeadline(ctx context.Context) bool {
+	if r.opts.minRefreshDeadline <= 0 {
+		return false
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return false
+	}
+	return time.Until(deadline) < r.opts.minRefreshDeadline
 }
 
 // Refresh refreshes the cache storage with the latest channels.
+// if another refresh completed while waiting, or within the interval set by WithMinRefreshInterval, it is skipped.
+// it is also skipped while the resolver is pinned by Pin.
+// refreshes never run concurrently. an explicit Refresh waits for the in-flight one and runs after it unless skipped,
+// while a lazy refresh triggered by NeedRefresh on lookup only waits for the in-flight one and never starts another.
 func (r *Resolver) Refresh(ctx context.Context) error {
+	return r.refreshOnce(ensureContext(ctx), false)
+}
+
+// RefreshAndLookup refreshes the cache storage unconditionally, then finds the channel by name,
+// such as right after the channel is known to have been created.
+// unlike Refresh, it is not skipped by WithMinRefreshInterval. it is only skipped while pinned,
+// or if another refresh completed while waiting for the in-flight one, since the cache is up to date then.
+func (r *Resolver) RefreshAndLookup(ctx context.Context, channelName string) (*slack.Channel, error) {
+	ctx = ensureContext(ctx)
+	channelName = r.opts.normalizeName(channelName)
+	if err := r.refreshOnce(ctx, true); err != nil {
+		return nil, err
+	}
+	channel, err := r.get(ctx, channelName)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			r.stats.misses.Add(1)
+		}
+		return nil, err
+	}
+	r.stats.hits.Add(1)
+	return channel, nil
+}
+
+// LookupFresh is like Lookup, but first refreshes the cache if the last refresh is older than maxAge,
+// regardless of the expiry of the cache storage, such as right after the channels were reorganized.
+// a cache this resolver has not refreshed yet, such as one loaded from a persistent storage, is refreshed too.
+// like RefreshAndLookup, the refresh is not skipped by WithMinRefreshInterval, only while pinned.
+func (r *Resolver) LookupFresh(ctx context.Context, channelName string, maxAge time.Duration) (*slack.Channel, error) {
+	ctx = ensureContext(ctx)
+	if !r.lastRefreshedWithin(maxAge) {
+		if err := r.refreshOnce(ctx, true); err != nil {
+			return nil, err
+		}
+	}
+	return r.Lookup(ctx, channelName)
+}
+
+// lastRefreshedWithin reports whether the last successful refresh completed within d. it waits for an in-flight refresh.
+func (r *Resolver) lastRefreshedWithin(d time.Duration) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return !r.lastRefreshed.IsZero() && time.Since(r.lastRefreshed) <= d
+}
+
+// refreshOnce runs a refresh unless skipped. force ignores WithMinRefreshInterval.
+func (r *Resolver) refreshOnce(ctx context.Context, force bool) error {
+	requested := time.Now()
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	var cursor string
-	var sleepTime time.Duration
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-time.After(sleepTime):
-		default:
-		}
-		channels, nextCursor, err := r.client.GetConversationsForUserContext(ctx, &slack.GetConversationsForUserParameters{
-			Cursor:          cursor,
-			Limit:           r.opts.batchSize,
-			ExcludeArchived: r.opts.excludeArchived,
-		})
-		if err != nil {
-			var rle *slack.RateLimitedError
-			if !errors.As(err, &rle) {
-				return err
-			}
-			if !rle.Retryable() {
-				return err
-			}
-			sleepTime = rle.RetryAfter
+	if r.isPinned() || r.lastRefreshed.After(requested) || (!force && r.recentlyRefreshed(requested)) {
+		return nil
+	}
+	r.refreshing.Store(true)
+	defer r.refreshing.Store(false)
+	started := time.Now()
+	r.differ.begin()
+	cached, err := r.refresh(ctx)
+	r.differ.commit(err == nil)
+	if err == nil {
+		r.lastRefreshed = time.Now()
+		r.stats.recordRefresh(cached)
+	}
+	r.health.record(err)
+	r.observeRefresh(ctx, started, err)
+	r.subscribers.publish(RefreshEvent{
+		Channels: cached,
+		Err:      err,
+		Time:     time.Now(),
+	})
+	return err
+}
+
+// RefreshUser runs only the users.conversations pass of Refresh, regardless of the refresh source options.
+// it is meant for diagnostics. the fetched channels are stored in the cache storage,
+// but it does not count as a refresh for Stats, Healthy, subscribers and WithMinRefreshInterval.
+func (r *Resolver) RefreshUser(ctx context.Context) error {
+	return r.refreshPass(ctx, sourceUserConversations, r.refreshUserConversations)
+}
+
+// RefreshPublic runs only the conversations.list pass of Refresh, regardless of the refresh source options.
+// it is meant for diagnostics like RefreshUser.
+func (r *Resolver) RefreshPublic(ctx context.Context) error {
+	return r.refreshPass(ctx, sourcePublicChannels, r.refreshPublicChannels)
+}
+
+func (r *Resolver) refreshPass(ctx context.Context, source refreshSource, fetch func(ctx context.Context) ([]slack.Channel, string, error)) error {
+	ctx = ensureContext(ctx)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.refreshing.Store(true)
+	defer r.refreshing.Store(false)
+	r.resetRefreshCounters()
+	channels, cursor, err := fetch(ctx)
+	if err != nil {
+		return err
+	}
+	channels = r.opts.filterChannels(channels)
+	if err := r.setChannels(ctx, channels, false); err != nil {
+		return err
+	}
+	return r.saveSyncCursor(ctx, source, cursor)
+}
+
+// WithTolerateMissingPublicScope logs a warning instead of failing the refresh,
+// when the public channels pass fails due to a missing scope.
+// the channels already fetched by the user conversations pass are kept.
+// it has no effect with WithPublicChannelsOnly, since there is no other pass.
+func WithTolerateMissingPublicScope() ResolverOption {
+	return func(o *resolverOptions) {
+		o.tolerateMissingScope = true
+	}
+}
+
+func (r *Resolver) tolerateMissingPublicScope(err error) bool {
+	if !r.opts.tolerateMissingScope || !r.opts.useUserConversations() {
+		return false
+	}
+	// a scope revoked during the pass is a failure, not a missing scope to tolerate.
+	return isMissingScope(err) && !errors.Is(err, ErrAuthRevoked)
+}
+
+// ErrAuthRevoked is returned when a page of a refresh fails with missing_scope or not_authed after earlier pages succeeded,
+// meaning the scope or the token was revoked during the refresh.
+// the refresh fails as a whole, so the cache is neither partially replaced nor marked fresh.
+var ErrAuthRevoked = errors.New("token scope or authentication revoked during refresh")
+
+func isAuthError(err error) bool {
+	var slackErr slack.SlackErrorResponse
+	if !errors.As(err, &slackErr) {
+		return false
+	}
+	return slackErr.Err == "missing_scope" || slackErr.Err == "not_authed" || slackErr.Err == "invalid_auth" || slackErr.Err == "token_revoked"
+}
+
+func isMissingScope(err error) bool {
+	var slackErr slack.SlackErrorResponse
+	if !errors.As(err, &slackErr) {
+		return false
+	}
+	return slackErr.Err == "missing_scope"
+}
+
+// ErrMaxAPICallsExceeded is returned when a refresh exceeds the limit set by WithMaxAPICallsPerRefresh.
+var ErrMaxAPICallsExceeded = errors.New("max api calls per refresh exceeded")
+
+// recentlyRefreshed reports whether the refresh requested at the given time can be skipped. must be called with mu held.
+func (r *Resolver) recentlyRefreshed(requested time.Time) bool {
+	if r.lastRefreshed.IsZero() {
+		return false
+	}
+	if r.lastRefreshed.After(requested) {
+		return true
+	}
+	return r.opts.minRefreshInterval > 0 && requested.Sub(r.lastRefreshed) < r.opts.minRefreshInterval
+}
+
+// refresh fetches the channels from all sources, and writes them to the cache storage at once,
+// so that a failure never leaves the cache partially updated and claiming freshness.
+func (r *Resolver) refresh(ctx context.Context) (int64, error) {
+	r.resetRefreshCounters()
+	if r.opts.workspaceGuard {
+		if err := r.guardWorkspace(ctx); err != nil {
+			return 0, err
+		}
+	}
+	if r.opts.checkpointEvery > 0 {
+		r.checkpoint = r.newRefreshCheckpoint()
+		defer func() { r.checkpoint = nil }()
+	}
+	tasks := r.refreshTasks()
+	results, err := r.runRefreshTasks(ctx, tasks)
+	if err != nil {
+		return 0, err
+	}
+	complete := true
+	var channels []slack.Channel
+	cursors := make(map[refreshSource]string, len(tasks))
+	for i, result := range results {
+		if result.err != nil {
+			r.logger(ctx).WarnContext(ctx, "public channels pass skipped due to missing scope, channels:read is required for WithSearchPublicChannels", "error", result.err)
+			complete = false
 			continue
 		}
-		if err := r.opts.cacheStorage.SetChannels(ctx, channels); err != nil {
-			return err
+		channels = append(channels, result.channels...)
+		cursors[tasks[i].source] = result.cursor
+	}
+	if r.truncated.Load() {
+		complete = false
+	}
+	if !complete {
+		r.differ.markPartial()
+	}
+	channels = r.opts.filterChannels(channels)
+	if r.opts.requireNonEmpty && !r.opts.incrementalSync && len(channels) == 0 {
+		return 0, ErrEmptyWorkspace
+	}
+	replace := r.opts.atomicReplace && complete && !r.opts.incrementalSync
+	if err := r.setChannels(ctx, channels, replace); err != nil {
+		return 0, err
+	}
+	if r.opts.recordSource {
+		r.sources.record(tasks, results, r.opts.incrementalSync)
+	}
+	for source, cursor := range cursors {
+		if err := r.saveSyncCursor(ctx, source, cursor); err != nil {
+			return 0, err
 		}
-		if nextCursor == "" {
-			break
+	}
+	if r.checkpoint != nil && complete && !r.opts.incrementalSync {
+		if err := r.evictRemoved(ctx, channels); err != nil {
+			return 0, err
 		}
-		cursor = nextCursor
 	}
-	if !r.opts.searchpublicChannels {
+	return int64(len(channels)), nil
+}
+
+// filterChannels returns the channels indexed on refresh, applying WithMemberOnly and WithArchivedOnly.
+// the given slice is not modified.
+func (o *resolverOptions) filterChannels(channels []slack.Channel) []slack.Channel {
+	if o.memberOnly {
+		channels = memberChannels(channels)
+	}
+	if o.archivedOnly {
+		channels = archivedChannels(channels)
+	}
+	return channels
+}
+
+func archivedChannels(channels []slack.Channel) []slack.Channel {
+	archived := make([]slack.Channel, 0, len(channels))
+	for _, channel := range channels {
+		if channel.IsArchived {
+			archived = append(archived, channel)
+		}
+	}
+	return archived
+}
+
+func memberChannels(channels []slack.Channel) []slack.Channel {
+	members := make([]slack.Channel, 0, len(channels))
+	for _, channel := range channels {
+		if channel.IsMember {
+			members = append(members, channel)
+		}
+	}
+	return members
+}
+
+// setChannels writes the refreshed channels to the cache storage, replacing all cached channels if replace is true.
+func (r *Resolver) setChannels(ctx context.Context, channels []slack.Channel, replace bool) error {
+	channels = r.storedChannels(channels)
+	writes := channels
+	// a replace drops the channels not written, so it always writes the full set.
+	// so does a storage evicting channels by WithMaxEntries, since the evicted channels are not known.
+	if r.opts.writeOnlyChanged && !replace && r.opts.maxEntries <= 0 {
+		writes = r.changes.changed(channels, r.opts.channelEqual)
+	}
+	write := r.writeChannels
+	if replace {
+		write = r.replaceChannels
+	}
+	if err := write(ctx, writes); err != nil {
+		return err
+	}
+	if r.opts.writeOnlyChanged {
+		r.changes.commit(writes)
+	}
+	if r.opts.trackRenames {
+		// the renames are resolved from the normalized names lookups query.
+		r.renames.observe(r.opts.normalizeChannels(channels), r.opts.renameRetention)
+	}
+	r.differ.collect(channels)
+	return nil
+}
+
+// resetRefreshCounters resets the counters of a refresh, checked by WithMaxAPICallsPerRefresh and WithMaxChannels.
+func (r *Resolver) resetRefreshCounters() {
+	r.apiCalls.Store(0)
+	r.loadedChannels.Store(0)
+	r.truncated.Store(false)
+}
+
+// limitChannels counts the fetched channels against WithMaxChannels, and truncates the page to the remaining count.
+// it reports whether the limit is reached.
+func (r *Resolver) limitChannels(channels []slack.Channel) ([]slack.Channel, bool) {
+	if r.opts.maxChannels <= 0 {
+		return channels, false
+	}
+	over := r.loadedChannels.Add(int64(len(channels))) - int64(r.opts.maxChannels)
+	if over < 0 {
+		return channels, false
+	}
+	return channels[:max(int64(len(channels))-over, 0)], true
+}
+
+// maxChannelsReached reports whether the refresh already fetched the channels allowed by WithMaxChannels.
+func (r *Resolver) maxChannelsReached() bool {
+	return r.opts.maxChannels > 0 && r.loadedChannels.Load() >= int64(r.opts.maxChannels)
+}
+
+// truncate records that the refresh stopped early by WithMaxChannels.
+func (r *Resolver) truncate(ctx context.Context, source refreshSource) {
+	r.truncated.Store(true)
+	r.logger(ctx).WarnContext(ctx, "refresh stopped at max channels, the cache is truncated", "source", source, "max_channels", r.opts.maxChannels)
+}
+
+func (r *Resolver) countAPICall() error {
+	if r.opts.maxAPICalls > 0 && r.apiCalls.Add(1) > int64(r.opts.maxAPICalls) {
+		return fmt.Errorf("%w: limit is %d", ErrMaxAPICallsExceeded, r.opts.maxAPICalls)
+	}
+	return nil
+}
+
+// sleepContext waits for d, and returns the error of ctx if it is done first or already.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
 		return nil
 	}
-	cursor = ""
-	sleepTime = 0
+}
+
+// refreshSource is the API used to fetch channels in a refresh pass.
+type refreshSource string
+
+const (
+	sourceUserConversations  refreshSource = "users.conversations"
+	sourcePublicChannels     refreshSource = "conversations.list"
+	sourceAdminConversations refreshSource = "admin.conversations.search"
+)
+
+// refreshUserConversations fetches the channels the bot is a member of with users.conversations API.
+func (r *Resolver) refreshUserConversations(ctx context.Context) ([]slack.Channel, string, error) {
+	return r.paginate(ctx, sourceUserConversations, r.fetchUserConversationsPage)
+}
+
+func (r *Resolver) fetchUserConversationsPage(ctx context.Context, cursor string) ([]slack.Channel, string, error) {
+	return r.client.GetConversationsForUserContext(ctx, &slack.GetConversationsForUserParameters{
+		Cursor:          cursor,
+		Types:           r.opts.conversationTypes(),
+		Limit:           r.opts.batchSize,
+		ExcludeArchived: r.opts.excludeArchived,
+	})
+}
+
+// refreshPublicChannels fetches the public channels with conversations.list API.
+func (r *Resolver) refreshPublicChannels(ctx context.Context) ([]slack.Channel, string, error) {
+	return r.paginate(ctx, sourcePublicChannels, r.fetchPublicChannelsPage)
+}
+
+func (r *Resolver) fetchPublicChannelsPage(ctx context.Context, cursor string) ([]slack.Channel, string, error) {
+	return r.client.GetConversationsContext(ctx, &slack.GetConversationsParameters{
+		Cursor:          cursor,
+		Limit:           r.opts.batchSize,
+		ExcludeArchived: r.opts.excludeArchived,
+	})
+}
+
+type fetchPageFunc func(ctx context.Context, cursor string) (channels []slack.Channel, nextCursor string, err error)
+
+// paginate fetches all pages of the source, retrying on rate limit.
+// it returns the channels and the cursor of the last page.
+func (r *Resolver) paginate(ctx context.Context, source refreshSource, fetch fetchPageFunc) ([]slack.Channel, string, error) {
+	var fetched []slack.Channel
+	cursor := r.loadSyncCursor(ctx, source)
+	var sleepTime time.Duration
+	pages := 0
 	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-time.After(sleepTime):
-		default:
-		}
-		channels, nextCursor, err := r.client.GetConversationsContext(ctx, &slack.GetConversationsParameters{
-			Cursor:          cursor,
-			Limit:           r.opts.batchSize,
-			ExcludeArchived: r.opts.excludeArchived,
-		})
+		if err := sleepContext(ctx, sleepTime); err != nil {
+			return nil, "", &RefreshCanceledError{Source: Source(source), Pages: pages, Err: err}
+		}
+		sleepTime = 0
+		if r.maxChannelsReached() {
+			r.truncate(ctx, source)
+			break
+		}
+		if err := r.countAPICall(); err != nil {
+			return nil, "", err
+		}
+		callCtx, cancel := r.callContext(ctx)
+		channels, nextCursor, err := fetch(callCtx, cursor)
+		cancel()
 		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, "", &RefreshCanceledError{Source: Source(source), Pages: pages, Err: ctxErr}
+			}
+			if cursor != "" && r.opts.incrementalSync && isInvalidCursor(err) {
+				// the persisted cursor is no longer valid, fall back to full pagination.
+				cursor = ""
+				continue
+			}
+			if pages > 0 && isAuthError(err) {
+				return nil, "", fmt.Errorf("%w: %s failed after %d pages: %w", ErrAuthRevoked, source, pages, err)
+			}
 			var rle *slack.RateLimitedError
 			if !errors.As(err, &rle) {
-				return err
+				return nil, "", err
 			}
 			if !rle.Retryable() {
-				return err
+				return nil, "", err
 			}
 			sleepTime = rle.RetryAfter
 			continue
 		}
-		if err := r.opts.cacheStorage.SetChannels(ctx, channels); err != nil {
-			return err
+		pages++
+		kept, reached := r.limitChannels(channels)
+		fetched = append(fetched, kept...)
+		if err := r.checkpoint.page(ctx, kept); err != nil {
+			return nil, "", err
+		}
+		if reached && (len(kept) < len(channels) || nextCursor != "") {
+			r.truncate(ctx, source)
+			break
 		}
 		if nextCursor == "" {
 			break
 		}
 		cursor = nextCursor
 	}
-	return nil
+	return fetched, cursor, nil
 }
@@ -1,7 +1,12 @@
 package slackcnr_test
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
 	"testing"
 	"time"
 
@@ -104,16 +109,6 @@ func TestResolverLookup__NoCache(t *testing.T) {
 			},
 		},
 	}, "test_cusor", nil)
-	storage.On("SetChannels", mock.Anything, []slack.Channel{
-		{
-			GroupConversation: slack.GroupConversation{
-				Conversation: slack.Conversation{
-					ID: "C012345678",
-				},
-				Name: "test",
-			},
-		},
-	}).Return(nil)
 	client.On("GetConversationsForUserContext", mock.Anything, &slack.GetConversationsForUserParameters{
 		Cursor:          "test_cusor",
 		Limit:           1,
@@ -129,6 +124,14 @@ func TestResolverLookup__NoCache(t *testing.T) {
 		},
 	}, "", nil)
 	storage.On("SetChannels", mock.Anything, []slack.Channel{
+		{
+			GroupConversation: slack.GroupConversation{
+				Conversation: slack.Conversation{
+					ID: "C012345678",
+				},
+				Name: "test",
+			},
+		},
 		{
 			GroupConversation: slack.GroupConversation{
 				Conversation: slack.Conversation{
@@ -181,16 +184,6 @@ func TestResolverLookup__RefreshOnCacheMiss(t *testing.T) {
 			},
 		},
 	}, "test_cusor", nil)
-	storage.On("SetChannels", mock.Anything, []slack.Channel{
-		{
-			GroupConversation: slack.GroupConversation{
-				Conversation: slack.Conversation{
-					ID: "C012345678",
-				},
-				Name: "test",
-			},
-		},
-	}).Return(nil)
 	client.On("GetConversationsForUserContext", mock.Anything, &slack.GetConversationsForUserParameters{
 		Cursor:          "test_cusor",
 		Limit:           1,
@@ -206,6 +199,14 @@ func TestResolverLookup__RefreshOnCacheMiss(t *testing.T) {
 		},
 	}, "", nil)
 	storage.On("SetChannels", mock.Anything, []slack.Channel{
+		{
+			GroupConversation: slack.GroupConversation{
+				Conversation: slack.Conversation{
+					ID: "C012345678",
+				},
+				Name: "test",
+			},
+		},
 		{
 			GroupConversation: slack.GroupConversation{
 				Conversation: slack.Conversation{
@@ -236,3 +237,962 @@ func TestResolverLookup__RefreshOnCacheMiss(t *testing.T) {
 	require.NotNil(t, channel)
 	require.Equal(t, "C012345678", channel.ID)
 }
+
+func TestResolverLookup__RefreshOnAnyError(t *testing.T) {
+	errRead := errors.New("transient read error")
+	cases := []struct {
+		name     string
+		opt      slackcnr.ResolverOption
+		expected error
+	}{
+		{name: "refresh on cache miss is strict", opt: slackcnr.WithRefreshOnCacheMiss(), expected: errRead},
+		{name: "refresh on any error", opt: slackcnr.WithRefreshOnAnyError()},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client := &mockSlackClient{t: t}
+			storage := &mockStorage{t: t}
+			defer storage.AssertExpectations(t)
+			storage.On("NeedRefresh", mock.Anything).Return(false).Once()
+			storage.On("GetByChannelName", mock.Anything, "test").Return(nil, errRead).Once()
+			if c.expected == nil {
+				client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).
+					Return([]slack.Channel{newTestChannel("C012345678", "test")}, "", nil).Once()
+				storage.On("SetChannels", mock.Anything, mock.Anything).Return(nil).Once()
+				storage.On("GetByChannelName", mock.Anything, "test").Return(&slack.Channel{
+					GroupConversation: slack.GroupConversation{
+						Conversation: slack.Conversation{ID: "C012345678"},
+						Name:         "test",
+					},
+				}, nil).Once()
+			}
+			r := slackcnr.New(client,
+				slackcnr.WithCacheStorage(storage),
+				c.opt,
+			)
+			channel, err := r.Lookup(context.Background(), "test")
+			client.AssertExpectations(t)
+			if c.expected != nil {
+				require.ErrorIs(t, err, c.expected)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "C012345678", channel.ID)
+		})
+	}
+}
+
+func TestNew__ClampBatchSize(t *testing.T) {
+	cases := []struct {
+		name      string
+		batchSize int
+		expected  int
+	}{
+		{name: "zero", batchSize: 0, expected: 1},
+		{name: "negative", batchSize: -10, expected: 1},
+		{name: "oversized", batchSize: 5000, expected: 1000},
+		{name: "valid", batchSize: 200, expected: 200},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client := &mockSlackClient{t: t}
+			defer client.AssertExpectations(t)
+			client.On("GetConversationsForUserContext", mock.Anything, &slack.GetConversationsForUserParameters{
+				Limit: c.expected,
+			}).Return([]slack.Channel{}, "", nil).Times(1)
+			r := slackcnr.New(client, slackcnr.WithBatchSize(c.batchSize))
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			require.NoError(t, r.Refresh(ctx))
+		})
+	}
+}
+
+func TestNewChecked__InvalidBatchSize(t *testing.T) {
+	for _, size := range []int{0, -1, 1001} {
+		_, err := slackcnr.NewChecked(&mockSlackClient{t: t}, slackcnr.WithBatchSize(size))
+		require.Error(t, err, "batch size %d", size)
+	}
+	r, err := slackcnr.NewChecked(&mockSlackClient{t: t}, slackcnr.WithBatchSize(1000))
+	require.NoError(t, err)
+	require.NotNil(t, r)
+}
+
+func TestNewChecked__NilClient(t *testing.T) {
+	_, err := slackcnr.NewChecked(nil)
+	require.Error(t, err)
+}
+
+func TestResolverSearchContains__NotSupported(t *testing.T) {
+	r := slackcnr.New(&mockSlackClient{t: t}, slackcnr.WithCacheStorage(&mockStorage{t: t}))
+	_, err := r.SearchContains(context.Background(), "test", 0)
+	require.ErrorIs(t, err, slackcnr.ErrSearchNotSupported)
+}
+
+func TestResolverLookup__SkipRefreshWhenInsufficientDeadline(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	storage := &mockStorage{t: t}
+	defer storage.AssertExpectations(t)
+
+	storage.On("NeedRefresh", mock.Anything).Return(true).Times(1)
+	storage.On("GetByChannelName", mock.Anything, "test").Return(&slack.Channel{
+		GroupConversation: slack.GroupConversation{
+			Conversation: slack.Conversation{
+				ID: "C012345678",
+			},
+			Name: "test",
+		},
+	}, nil)
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(storage),
+		slackcnr.WithSkipRefreshWhenInsufficientDeadline(time.Second),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	channel, err := r.Lookup(ctx, "test")
+	require.NoError(t, err)
+	require.Equal(t, "C012345678", channel.ID)
+	require.EqualValues(t, 1, r.Stats().Degraded)
+}
+
+func TestResolverRefresh__PublicChannelsOnly(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsContext", mock.Anything, &slack.GetConversationsParameters{
+		Limit: 1000,
+	}).Return([]slack.Channel{}, "", nil).Times(1)
+	r := slackcnr.New(client, slackcnr.WithPublicChannelsOnly())
+	require.NoError(t, r.Refresh(context.Background()))
+	client.AssertNotCalled(t, "GetConversationsForUserContext", mock.Anything, mock.Anything)
+}
+
+func TestNewChecked__ConflictingSources(t *testing.T) {
+	_, err := slackcnr.NewChecked(&mockSlackClient{t: t},
+		slackcnr.WithUserConversationsOnly(),
+		slackcnr.WithPublicChannelsOnly(),
+	)
+	require.Error(t, err)
+	_, err = slackcnr.NewChecked(&mockSlackClient{t: t},
+		slackcnr.WithUserConversationsOnly(),
+		slackcnr.WithSearchPublicChannels(),
+	)
+	require.Error(t, err)
+	_, err = slackcnr.NewChecked(&mockSlackClient{t: t},
+		slackcnr.WithPublicChannelsOnly(),
+		slackcnr.WithSearchPublicChannels(),
+	)
+	require.NoError(t, err)
+}
+
+func TestResolverRefresh__MaxAPICallsPerRefresh(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{}, "", nil).Times(1)
+	client.On("GetConversationsContext", mock.Anything, mock.Anything).Return([]slack.Channel{}, "next_cursor", nil)
+	r := slackcnr.New(client,
+		slackcnr.WithSearchPublicChannels(),
+		slackcnr.WithMaxAPICallsPerRefresh(3),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	err := r.Refresh(ctx)
+	require.ErrorIs(t, err, slackcnr.ErrMaxAPICallsExceeded)
+	client.AssertNumberOfCalls(t, "GetConversationsContext", 2)
+}
+
+func TestResolverRefresh__MaxChannels(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.MatchedBy(func(params *slack.GetConversationsForUserParameters) bool {
+		return params.Cursor == ""
+	})).Return([]slack.Channel{
+		newTestChannel("C000000001", "channel-1"),
+		newTestChannel("C000000002", "channel-2"),
+		newTestChannel("C000000003", "channel-3"),
+	}, "next", nil).Once()
+	client.On("GetConversationsForUserContext", mock.Anything, mock.MatchedBy(func(params *slack.GetConversationsForUserParameters) bool {
+		return params.Cursor == "next"
+	})).Return([]slack.Channel{
+		newTestChannel("C000000004", "channel-4"),
+		newTestChannel("C000000005", "channel-5"),
+		newTestChannel("C000000006", "channel-6"),
+	}, "more", nil).Once()
+	storage := slackcnr.NewInMemoryStorage(0)
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(storage),
+		slackcnr.WithSearchPublicChannels(),
+		slackcnr.WithMaxChannels(4),
+	)
+	ctx := context.Background()
+	require.NoError(t, r.Refresh(ctx), "a truncated refresh succeeds")
+	channels, err := storage.List(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"channel-1", "channel-2", "channel-3", "channel-4"}, channelNames(channels))
+	client.AssertNotCalled(t, "GetConversationsContext", mock.Anything, mock.Anything)
+}
+
+func TestResolverMustLookup(t *testing.T) {
+	storage := slackcnr.NewInMemoryStorage(0)
+	require.NoError(t, storage.SetChannels(context.Background(), []slack.Channel{
+		newTestChannel("C012345678", "test"),
+	}))
+	r := slackcnr.New(&mockSlackClient{t: t}, slackcnr.WithCacheStorage(storage))
+	require.Equal(t, "C012345678", r.MustLookup(context.Background(), "test").ID)
+	require.PanicsWithValue(t, "slackcnr: lookup channel `unknown`: channel not found", func() {
+		r.MustLookup(context.Background(), "unknown")
+	})
+}
+
+func TestResolverFind(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	errRefresh := errors.New("refresh failed")
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{}, "", errRefresh).Once()
+	storage := slackcnr.NewInMemoryStorage(0)
+	require.NoError(t, storage.SetChannels(context.Background(), []slack.Channel{
+		newTestChannel("C012345678", "test"),
+	}))
+	r := slackcnr.New(client, slackcnr.WithCacheStorage(storage), slackcnr.WithRefreshOnCacheMiss())
+	ctx := context.Background()
+
+	channel, ok, err := r.Find(ctx, "test")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "C012345678", channel.ID)
+
+	channel, ok, err = r.Find(ctx, "unknown")
+	require.ErrorIs(t, err, errRefresh, "a failed refresh is a real failure")
+	require.False(t, ok)
+	require.Nil(t, channel)
+
+	r = slackcnr.New(client, slackcnr.WithCacheStorage(storage))
+	channel, ok, err = r.Find(ctx, "unknown")
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Nil(t, channel)
+}
+
+func TestResolverRefreshAndLookup(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C012345678", "test"),
+		newTestChannel("C023456789", "just-created"),
+	}, "", nil).Twice()
+	storage := slackcnr.NewInMemoryStorage(0)
+	require.NoError(t, storage.SetChannels(context.Background(), []slack.Channel{
+		newTestChannel("C012345678", "test"),
+	}))
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(storage),
+		slackcnr.WithMinRefreshInterval(time.Hour),
+	)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		channel, err := r.RefreshAndLookup(ctx, "just-created")
+		require.NoError(t, err)
+		require.Equal(t, "C023456789", channel.ID)
+	}
+	client.AssertNumberOfCalls(t, "GetConversationsForUserContext", 2)
+
+	_, err := r.Lookup(ctx, "just-created")
+	require.NoError(t, err)
+	client.AssertNumberOfCalls(t, "GetConversationsForUserContext", 2)
+}
+
+func TestResolverLookupBestEffort(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{}, "", errors.New("api error")).Once()
+	storage := slackcnr.NewInMemoryStorage(0)
+	require.NoError(t, storage.SetChannels(context.Background(), []slack.Channel{
+		newTestChannel("C012345678", "test"),
+	}))
+	var buf bytes.Buffer
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(storage),
+		slackcnr.WithRefreshOnCacheMiss(),
+		slackcnr.WithLogger(slog.New(slog.NewTextHandler(&buf, nil))),
+	)
+	ctx := context.Background()
+
+	channel := r.LookupBestEffort(ctx, "test")
+	require.NotNil(t, channel)
+	require.Equal(t, "C012345678", channel.ID)
+	require.NotPanics(t, func() {
+		require.Nil(t, r.LookupBestEffort(ctx, "unknown"), "an api error is swallowed")
+	})
+	require.Contains(t, buf.String(), "api error")
+
+	r = slackcnr.New(client, slackcnr.WithCacheStorage(storage))
+	require.Nil(t, r.LookupBestEffort(ctx, "unknown"), "a miss is nil")
+}
+
+func TestResolverLookupForPost(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	member := newTestChannel("C012345678", "general")
+	member.IsMember = true
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		member,
+		newTestChannel("C023456789", "announcements"),
+	}, "", nil).Once()
+	r := slackcnr.New(client, slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)))
+	ctx := context.Background()
+
+	channel, err := r.LookupForPost(ctx, "general")
+	require.NoError(t, err)
+	require.Equal(t, "C012345678", channel.ID)
+
+	channel, err = r.LookupForPost(ctx, "announcements")
+	require.ErrorIs(t, err, slackcnr.ErrNotMember)
+	require.NotNil(t, channel, "the channel is returned to join it")
+	require.Equal(t, "C023456789", channel.ID)
+	_, err = r.Lookup(ctx, "announcements")
+	require.NoError(t, err, "Lookup does not check the membership")
+
+	_, err = r.LookupForPost(ctx, "unknown")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound)
+}
+
+func TestResolver__NilContext(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C012345678", "test"),
+	}, "", nil).Once()
+	r := slackcnr.New(client, slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)))
+	var ctx context.Context
+	require.NotPanics(t, func() {
+		channel, err := r.Lookup(ctx, "test")
+		require.NoError(t, err)
+		require.Equal(t, "C012345678", channel.ID)
+	})
+}
+
+func TestResolverRefresh__MinRefreshInterval(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C012345678", "test"),
+	}, "", nil).Once()
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+		slackcnr.WithMinRefreshInterval(time.Minute),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	_, err := r.Lookup(ctx, "test")
+	require.NoError(t, err)
+	require.NoError(t, r.Refresh(ctx))
+	client.AssertNumberOfCalls(t, "GetConversationsForUserContext", 1)
+}
+
+func TestResolverRefresh__Overlapping(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{}, "", nil).Run(func(mock.Arguments) {
+		close(started)
+		<-release
+	}).Once()
+	r := slackcnr.New(client, slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)))
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- r.Refresh(ctx)
+	}()
+	<-started
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+	}()
+	require.NoError(t, r.Refresh(ctx))
+	require.NoError(t, <-errCh)
+	client.AssertNumberOfCalls(t, "GetConversationsForUserContext", 1)
+}
+
+func TestResolverLookupLite(t *testing.T) {
+	archived := newTestChannel("C023456789", "archived")
+	archived.IsArchived = true
+	storage := slackcnr.NewInMemoryStorage(0)
+	require.NoError(t, storage.SetChannels(context.Background(), []slack.Channel{
+		newTestChannel("C012345678", "test"),
+		archived,
+	}))
+	r := slackcnr.New(&mockSlackClient{t: t}, slackcnr.WithCacheStorage(storage))
+
+	id, isArchived, err := r.LookupLite(context.Background(), "test")
+	require.NoError(t, err)
+	require.Equal(t, "C012345678", id)
+	require.False(t, isArchived)
+
+	id, isArchived, err = r.LookupLite(context.Background(), "archived")
+	require.NoError(t, err)
+	require.Equal(t, "C023456789", id)
+	require.True(t, isArchived)
+
+	_, _, err = r.LookupLite(context.Background(), "unknown")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound)
+}
+
+func TestResolverRefresh__TolerateMissingPublicScope(t *testing.T) {
+	missingScope := slack.SlackErrorResponse{Err: "missing_scope"}
+	cases := []struct {
+		name     string
+		opts     []slackcnr.ResolverOption
+		err      error
+		expected string
+	}{
+		{name: "tolerated", opts: []slackcnr.ResolverOption{slackcnr.WithTolerateMissingPublicScope()}, err: missingScope},
+		{name: "not_tolerated", err: missingScope, expected: "missing_scope"},
+		{name: "not_scope_error", opts: []slackcnr.ResolverOption{slackcnr.WithTolerateMissingPublicScope()}, err: slack.SlackErrorResponse{Err: "internal_error"}, expected: "internal_error"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client := &mockSlackClient{t: t}
+			defer client.AssertExpectations(t)
+			client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+				newTestChannel("C012345678", "test"),
+			}, "", nil).Once()
+			client.On("GetConversationsContext", mock.Anything, mock.Anything).Return([]slack.Channel{}, "", c.err).Once()
+			opts := append([]slackcnr.ResolverOption{
+				slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+				slackcnr.WithSearchPublicChannels(),
+				slackcnr.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+			}, c.opts...)
+			r := slackcnr.New(client, opts...)
+			err := r.Refresh(context.Background())
+			if c.expected != "" {
+				require.EqualError(t, err, c.expected)
+				return
+			}
+			require.NoError(t, err)
+			channel, err := r.Lookup(context.Background(), "test")
+			require.NoError(t, err)
+			require.Equal(t, "C012345678", channel.ID)
+		})
+	}
+}
+
+func TestResolverLookupTopicAndPurpose(t *testing.T) {
+	channel := newTestChannel("C012345678", "test")
+	channel.Topic = slack.Topic{Value: "the topic"}
+	channel.Purpose = slack.Purpose{Value: "the purpose"}
+	storage := slackcnr.NewInMemoryStorage(0)
+	require.NoError(t, storage.SetChannels(context.Background(), []slack.Channel{channel}))
+	r := slackcnr.New(&mockSlackClient{t: t}, slackcnr.WithCacheStorage(storage))
+	ctx := context.Background()
+
+	topic, err := r.LookupTopic(ctx, "test")
+	require.NoError(t, err)
+	require.Equal(t, "the topic", topic)
+	purpose, err := r.LookupPurpose(ctx, "test")
+	require.NoError(t, err)
+	require.Equal(t, "the purpose", purpose)
+
+	_, err = r.LookupTopic(ctx, "unknown")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound)
+	_, err = r.LookupPurpose(ctx, "unknown")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound)
+}
+
+type mockConversationInfoClient struct {
+	mockSlackClient
+}
+
+func (m *mockConversationInfoClient) GetConversationInfoContext(ctx context.Context, input *slack.GetConversationInfoInput) (*slack.Channel, error) {
+	args := m.Called(ctx, input)
+	channel, ok := args.Get(0).(*slack.Channel)
+	if channel != nil && !ok {
+		m.t.Error("failed to cast channel")
+	}
+	return channel, args.Error(1)
+}
+
+func TestResolverFetchAndCacheByID(t *testing.T) {
+	client := &mockConversationInfoClient{mockSlackClient{t: t}}
+	defer client.AssertExpectations(t)
+	channel := newTestChannel("C012345678", "test")
+	client.On("GetConversationInfoContext", mock.Anything, &slack.GetConversationInfoInput{
+		ChannelID: "C012345678",
+	}).Return(&channel, nil).Once()
+	r := slackcnr.New(client, slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)))
+	ctx := context.Background()
+
+	fetched, err := r.FetchAndCacheByID(ctx, "C012345678")
+	require.NoError(t, err)
+	require.Equal(t, "test", fetched.Name)
+	resolved, err := r.Lookup(ctx, "test")
+	require.NoError(t, err)
+	require.Equal(t, "C012345678", resolved.ID)
+	client.AssertNumberOfCalls(t, "GetConversationInfoContext", 1)
+	client.AssertNotCalled(t, "GetConversationsForUserContext", mock.Anything, mock.Anything)
+}
+
+func TestResolverFetchAndCacheByID__IncludeLocale(t *testing.T) {
+	client := &mockConversationInfoClient{mockSlackClient{t: t}}
+	defer client.AssertExpectations(t)
+	channel := newTestChannel("C012345678", "test")
+	channel.Locale = "ja-JP"
+	client.On("GetConversationInfoContext", mock.Anything, &slack.GetConversationInfoInput{
+		ChannelID:     "C012345678",
+		IncludeLocale: true,
+	}).Return(&channel, nil).Once()
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+		slackcnr.WithIncludeLocale(),
+	)
+	ctx := context.Background()
+
+	_, err := r.FetchAndCacheByID(ctx, "C012345678")
+	require.NoError(t, err)
+	locale, err := r.LookupLocale(ctx, "test")
+	require.NoError(t, err)
+	require.Equal(t, "ja-JP", locale)
+}
+
+func TestResolverFetchAndCacheByID__NotSupported(t *testing.T) {
+	r := slackcnr.New(&mockSlackClient{t: t})
+	_, err := r.FetchAndCacheByID(context.Background(), "C012345678")
+	require.ErrorIs(t, err, slackcnr.ErrConversationInfoNotSupported)
+}
+
+func TestResolverRefresh__WithoutUserConversations(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsContext", mock.Anything, mock.Anything).Return([]slack.Channel{}, "", nil).Once()
+	r, err := slackcnr.NewChecked(client,
+		slackcnr.WithoutUserConversations(),
+		slackcnr.WithSearchPublicChannels(),
+	)
+	require.NoError(t, err)
+	require.NoError(t, r.Refresh(context.Background()))
+	client.AssertNotCalled(t, "GetConversationsForUserContext", mock.Anything, mock.Anything)
+
+	_, err = slackcnr.NewChecked(client, slackcnr.WithoutUserConversations())
+	require.Error(t, err, "no refresh source is enabled")
+}
+
+func TestResolverRefresh__MemberOnly(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	member := newTestChannel("C012345678", "member")
+	member.IsMember = true
+	nonMember := newTestChannel("C023456789", "non-member")
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{}, "", nil).Once()
+	client.On("GetConversationsContext", mock.Anything, mock.Anything).Return([]slack.Channel{member, nonMember}, "", nil).Once()
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+		slackcnr.WithSearchPublicChannels(),
+		slackcnr.WithMemberOnly(),
+	)
+	ctx := context.Background()
+
+	resolved, err := r.Lookup(ctx, "member")
+	require.NoError(t, err)
+	require.Equal(t, "C012345678", resolved.ID)
+	_, err = r.Lookup(ctx, "non-member")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound)
+}
+
+func TestResolverLookup__WaitsForInFlightRefresh(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+		close(started)
+		<-release
+	}).Return([]slack.Channel{}, "", errors.New("refresh failed")).Once()
+	r := slackcnr.New(client, slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)))
+	ctx := context.Background()
+
+	manual := make(chan error, 1)
+	go func() {
+		manual <- r.Refresh(ctx)
+	}()
+	<-started
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := r.Lookup(ctx, "test")
+			require.ErrorIs(t, err, slackcnr.ErrNotFound)
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	require.Error(t, <-manual)
+	wg.Wait()
+	client.AssertNumberOfCalls(t, "GetConversationsForUserContext", 1)
+}
+
+func TestResolverRefreshUser(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C012345678", "user"),
+	}, "", nil).Once()
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+		slackcnr.WithSearchPublicChannels(),
+	)
+	ctx := context.Background()
+	require.NoError(t, r.RefreshUser(ctx))
+	client.AssertNotCalled(t, "GetConversationsContext", mock.Anything, mock.Anything)
+	resolved, err := r.Lookup(ctx, "user")
+	require.NoError(t, err)
+	require.Equal(t, "C012345678", resolved.ID)
+	require.Zero(t, r.Stats().Refreshes)
+}
+
+func TestResolverRefreshPublic(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C023456789", "public"),
+	}, "", nil).Once()
+	// the public pass runs even if the options disable it.
+	r := slackcnr.New(client, slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)))
+	ctx := context.Background()
+	require.NoError(t, r.RefreshPublic(ctx))
+	client.AssertNotCalled(t, "GetConversationsForUserContext", mock.Anything, mock.Anything)
+	resolved, err := r.Lookup(ctx, "public")
+	require.NoError(t, err)
+	require.Equal(t, "C023456789", resolved.ID)
+}
+
+func TestResolverRefreshPublic__Error(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsContext", mock.Anything, mock.Anything).Return([]slack.Channel{}, "", errors.New("boom")).Once()
+	r := slackcnr.New(client, slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)))
+	require.EqualError(t, r.RefreshPublic(context.Background()), "boom")
+}
+
+func TestResolverLookup__IncludeArchived(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	archived := newTestChannel("C012345678", "archived")
+	archived.IsArchived = true
+	client.On("GetConversationsForUserContext", mock.Anything, &slack.GetConversationsForUserParameters{
+		Limit:           1000,
+		ExcludeArchived: false,
+	}).Return([]slack.Channel{archived}, "", nil).Once()
+	// New lets WithIncludeArchived take precedence.
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+		slackcnr.WithExcludeArchived(),
+		slackcnr.WithIncludeArchived(),
+	)
+	require.False(t, r.Config().ExcludeArchived)
+	channel, err := r.Lookup(context.Background(), "archived")
+	require.NoError(t, err)
+	require.Equal(t, "C012345678", channel.ID)
+	require.True(t, channel.IsArchived)
+
+	_, err = slackcnr.NewChecked(client, slackcnr.WithExcludeArchived(), slackcnr.WithIncludeArchived())
+	require.Error(t, err)
+}
+
+func TestResolverLookup__ArchivedOnly(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	archived := newTestChannel("C012345678", "old-project")
+	archived.IsArchived = true
+	client.On("GetConversationsForUserContext", mock.Anything, &slack.GetConversationsForUserParameters{
+		Limit:           1000,
+		ExcludeArchived: false,
+	}).Return([]slack.Channel{
+		archived,
+		newTestChannel("C023456789", "general"),
+	}, "", nil).Once()
+	// New lets WithArchivedOnly take precedence.
+	storage := slackcnr.NewInMemoryStorage(0)
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(storage),
+		slackcnr.WithExcludeArchived(),
+		slackcnr.WithArchivedOnly(),
+	)
+	require.False(t, r.Config().ExcludeArchived)
+	require.True(t, r.Config().ArchivedOnly)
+	ctx := context.Background()
+	require.NoError(t, r.Refresh(ctx))
+	channels, err := storage.List(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"old-project"}, channelNames(channels))
+	_, err = r.Lookup(ctx, "general")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound)
+
+	unarchived := archived
+	unarchived.IsArchived = false
+	require.NoError(t, r.UpdateChannel(ctx, unarchived))
+	_, err = r.Lookup(ctx, "old-project")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound, "an unarchived channel is invalidated")
+
+	_, err = slackcnr.NewChecked(client, slackcnr.WithExcludeArchived(), slackcnr.WithArchivedOnly())
+	require.Error(t, err)
+}
+
+func TestResolverRefresh__RequireNonEmptyCache(t *testing.T) {
+	cases := []struct {
+		name     string
+		opts     []slackcnr.ResolverOption
+		expected error
+	}{
+		{name: "default"},
+		{name: "required", opts: []slackcnr.ResolverOption{slackcnr.WithRequireNonEmptyCache()}, expected: slackcnr.ErrEmptyWorkspace},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client := &mockSlackClient{t: t}
+			defer client.AssertExpectations(t)
+			client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{}, "", nil).Once()
+			r := slackcnr.New(client, append(c.opts, slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)))...)
+			err := r.Refresh(context.Background())
+			if c.expected == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, c.expected)
+		})
+	}
+
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C012345678", "general"),
+	}, "", nil).Once()
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+		slackcnr.WithRequireNonEmptyCache(),
+	)
+	require.NoError(t, r.Refresh(context.Background()))
+}
+
+func TestResolver__ConcurrentReadsDuringRefresh(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+		time.Sleep(time.Millisecond)
+	}).Return([]slack.Channel{
+		newTestChannel("C012345678", "general"),
+		newTestChannel("C023456789", "random"),
+	}, "", nil)
+	r := slackcnr.New(client, slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(time.Millisecond)))
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errCh := make(chan error, 16)
+	run := func(fn func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				if err := fn(); err != nil {
+					errCh <- err
+					return
+				}
+			}
+		}()
+	}
+	for i := 0; i < 8; i++ {
+		run(func() error {
+			_, err := r.Lookup(ctx, "general")
+			return err
+		})
+	}
+	run(func() error {
+		_, err := r.LookupByID(ctx, "C023456789")
+		return err
+	})
+	run(func() error {
+		_, err := r.ListNames(ctx)
+		return err
+	})
+	run(func() error {
+		_, err := r.SearchContains(ctx, "gen", 0)
+		return err
+	})
+	run(func() error {
+		return r.Refresh(ctx)
+	})
+	run(func() error {
+		return r.UpdateChannel(ctx, newTestChannel("C034567890", "extra"))
+	})
+	wg.Wait()
+	close(errCh)
+	for err := range errCh {
+		require.NoError(t, err)
+	}
+}
+
+func TestResolverRefresh__CancelAtPassBoundary(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+		cancel()
+	}).Return([]slack.Channel{newTestChannel("C012345678", "member")}, "", nil).Once()
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+		slackcnr.WithSearchPublicChannels(),
+	)
+	require.ErrorIs(t, r.Refresh(ctx), context.Canceled)
+	client.AssertNotCalled(t, "GetConversationsContext", mock.Anything, mock.Anything)
+}
+
+func TestResolverRefresh__RefreshCanceledError(t *testing.T) {
+	t.Run("user conversations", func(t *testing.T) {
+		client := &mockSlackClient{t: t}
+		defer client.AssertExpectations(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		client.On("GetConversationsForUserContext", mock.Anything, mock.MatchedBy(func(params *slack.GetConversationsForUserParameters) bool {
+			return params.Cursor == ""
+		})).Return([]slack.Channel{newTestChannel("C012345678", "general")}, "next", nil).Once()
+		client.On("GetConversationsForUserContext", mock.Anything, mock.MatchedBy(func(params *slack.GetConversationsForUserParameters) bool {
+			return params.Cursor == "next"
+		})).Run(func(mock.Arguments) {
+			cancel()
+		}).Return([]slack.Channel{}, "", context.Canceled).Once()
+		r := slackcnr.New(client, slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)))
+
+		err := r.Refresh(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+		var canceled *slackcnr.RefreshCanceledError
+		require.ErrorAs(t, err, &canceled)
+		require.Equal(t, slackcnr.SourceUserConversations, canceled.Source)
+		require.Equal(t, 1, canceled.Pages)
+	})
+	t.Run("public channels", func(t *testing.T) {
+		client := &mockSlackClient{t: t}
+		defer client.AssertExpectations(t)
+		client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{}, "", nil).Once()
+		client.On("GetConversationsContext", mock.Anything, mock.Anything).
+			Return([]slack.Channel{}, "", &slack.RateLimitedError{RetryAfter: time.Hour}).Once()
+		r := slackcnr.New(client,
+			slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+			slackcnr.WithSearchPublicChannels(),
+		)
+		// the deadline expires while waiting for the rate limit.
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		err := r.Refresh(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		var canceled *slackcnr.RefreshCanceledError
+		require.ErrorAs(t, err, &canceled)
+		require.Equal(t, slackcnr.SourcePublicChannels, canceled.Source)
+		require.Equal(t, 0, canceled.Pages)
+	})
+}
+
+func TestResolverLookupFresh(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C012345678", "general"),
+	}, "", nil).Once()
+	r := slackcnr.New(client, slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(24*time.Hour)))
+	ctx := context.Background()
+	require.NoError(t, r.Refresh(ctx))
+
+	channel, err := r.LookupFresh(ctx, "general", time.Minute)
+	require.NoError(t, err, "a cache younger than max age is served")
+	require.Equal(t, "C012345678", channel.ID)
+
+	time.Sleep(20 * time.Millisecond)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C012345678", "general-renamed"),
+		newTestChannel("C023456789", "general"),
+	}, "", nil).Once()
+	channel, err = r.LookupFresh(ctx, "general", 10*time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, "C023456789", channel.ID, "a cache older than max age is refreshed, even if not expired")
+}
+
+func TestResolverRefresh__RateLimitWait(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).
+		Return([]slack.Channel{}, "", &slack.RateLimitedError{RetryAfter: 50 * time.Millisecond}).Once()
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).
+		Return([]slack.Channel{newTestChannel("C012345678", "test")}, "", nil).Once()
+	r := slackcnr.New(client, slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)))
+	started := time.Now()
+	require.NoError(t, r.Refresh(context.Background()))
+	require.GreaterOrEqual(t, time.Since(started), 50*time.Millisecond, "the retry waits for RetryAfter")
+
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).
+		Return([]slack.Channel{}, "", &slack.RateLimitedError{RetryAfter: time.Hour}).Once()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	started = time.Now()
+	require.ErrorIs(t, r.Refresh(ctx), context.DeadlineExceeded)
+	require.Less(t, time.Since(started), 5*time.Second, "the wait is canceled with the context")
+}
+
+func TestResolverRefresh__ScopeRevokedMidPagination(t *testing.T) {
+	missingScope := slack.SlackErrorResponse{Err: "missing_scope"}
+	cases := []struct {
+		name string
+		opts []slackcnr.ResolverOption
+		mock func(client *mockSlackClient)
+	}{
+		{
+			name: "user conversations",
+			mock: func(client *mockSlackClient) {
+				client.On("GetConversationsForUserContext", mock.Anything, &slack.GetConversationsForUserParameters{Limit: 1000}).
+					Return([]slack.Channel{newTestChannel("C023456789", "page1")}, "cursor1", nil).Once()
+				client.On("GetConversationsForUserContext", mock.Anything, &slack.GetConversationsForUserParameters{Cursor: "cursor1", Limit: 1000}).
+					Return([]slack.Channel{}, "", missingScope).Once()
+			},
+		},
+		{
+			name: "public channels with tolerated missing scope",
+			opts: []slackcnr.ResolverOption{
+				slackcnr.WithSearchPublicChannels(),
+				slackcnr.WithTolerateMissingPublicScope(),
+			},
+			mock: func(client *mockSlackClient) {
+				client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).
+					Return([]slack.Channel{}, "", nil).Once()
+				client.On("GetConversationsContext", mock.Anything, &slack.GetConversationsParameters{Limit: 1000}).
+					Return([]slack.Channel{newTestChannel("C023456789", "page1")}, "cursor1", nil).Once()
+				client.On("GetConversationsContext", mock.Anything, &slack.GetConversationsParameters{Cursor: "cursor1", Limit: 1000}).
+					Return([]slack.Channel{}, "", missingScope).Once()
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client := &mockSlackClient{t: t}
+			defer client.AssertExpectations(t)
+			c.mock(client)
+			storage := slackcnr.NewInMemoryStorage(time.Millisecond)
+			require.NoError(t, storage.SetChannels(context.Background(), []slack.Channel{newTestChannel("C012345678", "old")}))
+			time.Sleep(5 * time.Millisecond)
+			r := slackcnr.New(client, append(c.opts, slackcnr.WithCacheStorage(storage))...)
+			ctx := context.Background()
+
+			err := r.Refresh(ctx)
+			require.ErrorIs(t, err, slackcnr.ErrAuthRevoked)
+			var slackErr slack.SlackErrorResponse
+			require.ErrorAs(t, err, &slackErr)
+			require.Equal(t, "missing_scope", slackErr.Err)
+			require.True(t, storage.NeedRefresh(ctx), "the cache is not marked fresh")
+			_, err = storage.GetByChannelName(ctx, "page1")
+			require.ErrorIs(t, err, slackcnr.ErrNotFound, "the cache is not partially updated")
+			_, err = storage.GetByChannelName(ctx, "old")
+			require.NoError(t, err)
+		})
+	}
+}
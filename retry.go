@@ -0,0 +1,108 @@
+package slackcnr
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+var _ Storage = (*RetryingStorage)(nil)
+
+// RetryingStorage is a Storage decorator that retries SetChannels and GetByChannelName with exponential backoff.
+// only errors reported as retryable by isRetryable are retried. NeedRefresh is passed through as is.
+// ErrNotFound is never retried.
+type RetryingStorage struct {
+	storage     Storage
+	isRetryable func(error) bool
+
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+// RetryingStorageOption is an option for NewRetryingStorage.
+type RetryingStorageOption func(*RetryingStorage)
+
+// WithRetryMaxAttempts sets the maximum number of attempts including the first one. the default is 3.
+func WithRetryMaxAttempts(n int) RetryingStorageOption {
+	return func(s *RetryingStorage) {
+		s.maxAttempts = n
+	}
+}
+
+// WithRetryBackoff sets the initial backoff and its upper bound. the backoff doubles on each retry.
+// the default is 100ms up to 5s.
+func WithRetryBackoff(initial, max time.Duration) RetryingStorageOption {
+	return func(s *RetryingStorage) {
+		s.initialBackoff = initial
+		s.maxBackoff = max
+	}
+}
+
+// NewRetryingStorage wraps the storage to retry its operations on errors for which isRetryable returns true.
+func NewRetryingStorage(storage Storage, isRetryable func(error) bool, optFns ...RetryingStorageOption) *RetryingStorage {
+	s := &RetryingStorage{
+		storage:        storage,
+		isRetryable:    isRetryable,
+		maxAttempts:    3,
+		initialBackoff: 100 * time.Millisecond,
+		maxBackoff:     5 * time.Second,
+	}
+	for _, optFn := range optFns {
+		optFn(s)
+	}
+	if s.maxAttempts < 1 {
+		s.maxAttempts = 1
+	}
+	return s
+}
+
+func (s *RetryingStorage) SetChannels(ctx context.Context, channels []slack.Channel) error {
+	return s.retry(ctx, func() error {
+		return s.storage.SetChannels(ctx, channels)
+	})
+}
+
+func (s *RetryingStorage) GetByChannelName(ctx context.Context, channelName string) (*slack.Channel, error) {
+	var channel *slack.Channel
+	err := s.retry(ctx, func() error {
+		var err error
+		channel, err = s.storage.GetByChannelName(ctx, channelName)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return channel, nil
+}
+
+func (s *RetryingStorage) NeedRefresh(ctx context.Context) bool {
+	return s.storage.NeedRefresh(ctx)
+}
+
+// Unwrap returns the wrapped storage.
+func (s *RetryingStorage) Unwrap() Storage {
+	return s.storage
+}
+
+func (s *RetryingStorage) retry(ctx context.Context, fn func() error) error {
+	backoff := s.initialBackoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = fn()
+		if err == nil || attempt >= s.maxAttempts || errors.Is(err, ErrNotFound) || s.isRetryable == nil || !s.isRetryable(err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if s.maxBackoff > 0 && backoff > s.maxBackoff {
+			backoff = s.maxBackoff
+		}
+	}
+}
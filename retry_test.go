@@ -0,0 +1,75 @@
+package slackcnr_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/require"
+)
+
+var errTransient = errors.New("transient")
+
+type flakyStorage struct {
+	*slackcnr.InMemoryStorage
+	failures int
+	calls    int
+}
+
+func (s *flakyStorage) SetChannels(ctx context.Context, channels []slack.Channel) error {
+	s.calls++
+	if s.calls <= s.failures {
+		return errTransient
+	}
+	return s.InMemoryStorage.SetChannels(ctx, channels)
+}
+
+func (s *flakyStorage) GetByChannelName(ctx context.Context, channelName string) (*slack.Channel, error) {
+	s.calls++
+	if s.calls <= s.failures {
+		return nil, errTransient
+	}
+	return s.InMemoryStorage.GetByChannelName(ctx, channelName)
+}
+
+func isTransient(err error) bool {
+	return errors.Is(err, errTransient)
+}
+
+func TestRetryingStorage__SucceedsAfterFailures(t *testing.T) {
+	ctx := context.Background()
+	backend := &flakyStorage{InMemoryStorage: slackcnr.NewInMemoryStorage(0), failures: 2}
+	storage := slackcnr.NewRetryingStorage(backend, isTransient, slackcnr.WithRetryBackoff(time.Millisecond, 2*time.Millisecond))
+	require.NoError(t, storage.SetChannels(ctx, []slack.Channel{newTestChannel("C1", "test")}))
+	require.Equal(t, 3, backend.calls)
+	require.False(t, storage.NeedRefresh(ctx))
+
+	backend.calls, backend.failures = 0, 2
+	channel, err := storage.GetByChannelName(ctx, "test")
+	require.NoError(t, err)
+	require.Equal(t, "C1", channel.ID)
+	require.Equal(t, 3, backend.calls)
+}
+
+func TestRetryingStorage__GivesUp(t *testing.T) {
+	ctx := context.Background()
+	backend := &flakyStorage{InMemoryStorage: slackcnr.NewInMemoryStorage(0), failures: 5}
+	storage := slackcnr.NewRetryingStorage(backend, isTransient,
+		slackcnr.WithRetryMaxAttempts(2),
+		slackcnr.WithRetryBackoff(time.Millisecond, time.Millisecond),
+	)
+	require.ErrorIs(t, storage.SetChannels(ctx, nil), errTransient)
+	require.Equal(t, 2, backend.calls)
+}
+
+func TestRetryingStorage__DoesNotRetryNonRetryable(t *testing.T) {
+	ctx := context.Background()
+	backend := &flakyStorage{InMemoryStorage: slackcnr.NewInMemoryStorage(0)}
+	storage := slackcnr.NewRetryingStorage(backend, func(error) bool { return true })
+	_, err := storage.GetByChannelName(ctx, "missing")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound)
+	require.Equal(t, 1, backend.calls)
+}
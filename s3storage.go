@@ -0,0 +1,276 @@
+package slackcnr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+	"github.com/slack-go/slack"
+)
+
+const s3LastSetTimeMetadataKey = "slackcnr-last-set-time"
+
+// S3Storage is a Storage that persists the whole channel set as a single JSON object in Amazon S3.
+// it is intended for serverless environments such as AWS Lambda, that have no persistent disk between cold starts.
+// SetChannels merges the channels into the object, use WithAtomicReplace to drop the channels deleted on Slack on a refresh.
+type S3Storage struct {
+	client         *s3.Client
+	bucket         string
+	key            string
+	expredDuration time.Duration
+
+	codec Codec
+
+	mu     sync.Mutex
+	loaded bool
+	cache  *InMemoryStorage
+	// lastSetTime is the last set time of the object the local copy was loaded from or saved as.
+	lastSetTime time.Time
+}
+
+// S3StorageOption is an option for NewS3Storage.
+type S3StorageOption func(*S3Storage)
+
+// WithS3Codec sets the codec to serialize channels.
+// by default, the object is a single JSON object.
+// when a codec is set, the object is a sequence of length-prefixed records encoded by the codec.
+func WithS3Codec(codec Codec) S3StorageOption {
+	return func(s *S3Storage) {
+		s.codec = codec
+	}
+}
+
+type s3StorageObject struct {
+	LastSetTime time.Time       `json:"last_set_time"`
+	Channels    []slack.Channel `json:"channels"`
+}
+
+// NewS3Storage creates a new S3 storage. if expredDuration is 0, it never expires.
+func NewS3Storage(client *s3.Client, bucket, key string, expredDuration time.Duration, optFns ...S3StorageOption) *S3Storage {
+	s := &S3Storage{
+		client:         client,
+		bucket:         bucket,
+		key:            key,
+		expredDuration: expredDuration,
+		cache:          NewInMemoryStorage(0),
+	}
+	for _, optFn := range optFns {
+		optFn(s)
+	}
+	return s
+}
+
+func (s *S3Storage) SetChannels(ctx context.Context, channels []slack.Channel) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(ctx); err != nil {
+		return err
+	}
+	if err := s.cache.SetChannels(ctx, channels); err != nil {
+		return err
+	}
+	return s.save(ctx)
+}
+
+var (
+	_ ReplaceableStorage = (*S3Storage)(nil)
+	_ DeletableStorage   = (*S3Storage)(nil)
+)
+
+// ReplaceChannels rewrites the object with the given channels only, so that channels deleted on Slack are dropped.
+// readers of the object see either the previous or the new object, since S3 replaces the object at once.
+func (s *S3Storage) ReplaceChannels(ctx context.Context, channels []slack.Channel) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.cache.ReplaceChannels(ctx, channels); err != nil {
+		return err
+	}
+	s.loaded = true
+	return s.save(ctx)
+}
+
+// DeleteChannels removes the channels and rewrites the object.
+func (s *S3Storage) DeleteChannels(ctx context.Context, ids ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(ctx); err != nil {
+		return err
+	}
+	if err := s.cache.DeleteChannels(ctx, ids...); err != nil {
+		return err
+	}
+	return s.save(ctx)
+}
+
+// save writes all channels of the local copy to the object, as set now.
+func (s *S3Storage) save(ctx context.Context) error {
+	lastSetTime := now()
+	body, contentType, err := s.encode(lastSetTime, s.cache.all())
+	if err != nil {
+		return err
+	}
+	_, err = s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      &s.bucket,
+		Key:         &s.key,
+		Body:        bytes.NewReader(body),
+		ContentType: stringPtr(contentType),
+		Metadata: map[string]string{
+			s3LastSetTimeMetadataKey: lastSetTime.Format(time.RFC3339Nano),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("put s3://%s/%s: %w", s.bucket, s.key, err)
+	}
+	s.lastSetTime = lastSetTime
+	return nil
+}
+
+func (s *S3Storage) GetByChannelName(ctx context.Context, channelName string) (*slack.Channel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(ctx); err != nil {
+		return nil, err
+	}
+	return s.cache.GetByChannelName(ctx, channelName)
+}
+
+// NeedRefresh reports whether the object is missing or expired.
+// the object is checked with a HEAD request only while the last set time seen locally is unknown or expired,
+// so that a lookup on a fresh cache costs no S3 request.
+// if another process has rewritten the object since, the local copy is loaded again.
+func (s *S3Storage) NeedRefresh(ctx context.Context) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.expired(s.lastSetTime) {
+		return false
+	}
+	output, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: &s.bucket,
+		Key:    &s.key,
+	})
+	if err != nil {
+		return true
+	}
+	lastSetTime, ok := s3LastSetTime(output.Metadata, output.LastModified)
+	if !ok {
+		return true
+	}
+	if lastSetTime.After(s.lastSetTime) {
+		s.loaded = false
+		if err := s.load(ctx); err != nil {
+			// the next read loads it again.
+			return s.expired(lastSetTime)
+		}
+	}
+	return s.expired(s.lastSetTime)
+}
+
+func (s *S3Storage) expired(lastSetTime time.Time) bool {
+	if lastSetTime.IsZero() {
+		return true
+	}
+	if s.expredDuration == 0 {
+		return false
+	}
+	return since(lastSetTime) > s.expredDuration
+}
+
+// s3LastSetTime returns the last set time of the object from its metadata, or its last modified time.
+func s3LastSetTime(metadata map[string]string, lastModified *time.Time) (time.Time, bool) {
+	if lastSetTime, err := time.Parse(time.RFC3339Nano, metadata[s3LastSetTimeMetadataKey]); err == nil {
+		return lastSetTime, true
+	}
+	if lastModified == nil {
+		return time.Time{}, false
+	}
+	return *lastModified, true
+}
+
+// load reads the object from S3 into the local copy, unless already loaded. a missing object is treated as an empty cache.
+func (s *S3Storage) load(ctx context.Context) error {
+	if s.loaded {
+		return nil
+	}
+	output, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: &s.bucket,
+		Key:    &s.key,
+	})
+	if err != nil {
+		if isS3NotFound(err) {
+			s.loaded = true
+			return nil
+		}
+		return fmt.Errorf("get s3://%s/%s: %w", s.bucket, s.key, err)
+	}
+	defer output.Body.Close()
+	body, err := io.ReadAll(output.Body)
+	if err != nil {
+		return fmt.Errorf("read s3://%s/%s: %w", s.bucket, s.key, err)
+	}
+	channels, err := s.decode(body)
+	if err != nil {
+		return fmt.Errorf("decode s3://%s/%s: %w", s.bucket, s.key, err)
+	}
+	if err := s.cache.ReplaceChannels(ctx, channels); err != nil {
+		return err
+	}
+	if lastSetTime, ok := s3LastSetTime(output.Metadata, output.LastModified); ok {
+		s.lastSetTime = lastSetTime
+	}
+	s.loaded = true
+	return nil
+}
+
+func (s *S3Storage) encode(lastSetTime time.Time, channels []slack.Channel) ([]byte, string, error) {
+	if s.codec != nil {
+		body, err := encodeChannels(s.codec, channels)
+		return body, "application/octet-stream", err
+	}
+	body, err := json.Marshal(s3StorageObject{
+		LastSetTime: lastSetTime,
+		Channels:    channels,
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("marshal channels: %w", err)
+	}
+	return body, "application/json", nil
+}
+
+func (s *S3Storage) decode(body []byte) ([]slack.Channel, error) {
+	if s.codec != nil {
+		return decodeChannels(s.codec, body)
+	}
+	var obj s3StorageObject
+	if err := json.Unmarshal(body, &obj); err != nil {
+		return nil, err
+	}
+	return obj.Channels, nil
+}
+
+func isS3NotFound(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.ErrorCode() {
+	case "NoSuchKey", "NotFound":
+		return true
+	}
+	return false
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,184 @@
+package slackcnr_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeS3Object struct {
+	body     []byte
+	metadata http.Header
+}
+
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string]fakeS3Object
+	heads   int
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch req.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(req.Body)
+		metadata := http.Header{}
+		for k, v := range req.Header {
+			if strings.HasPrefix(strings.ToLower(k), "x-amz-meta-") {
+				metadata[k] = v
+			}
+		}
+		f.objects[req.URL.Path] = fakeS3Object{body: body, metadata: metadata}
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet, http.MethodHead:
+		if req.Method == http.MethodHead {
+			f.heads++
+		}
+		obj, ok := f.objects[req.URL.Path]
+		if !ok {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusNotFound)
+			if req.Method == http.MethodGet {
+				io.WriteString(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>not found</Message></Error>`)
+			}
+			return
+		}
+		for k, v := range obj.metadata {
+			w.Header()[k] = v
+		}
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.WriteHeader(http.StatusOK)
+		if req.Method == http.MethodGet {
+			w.Write(obj.body)
+		}
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func newFakeS3Client(t *testing.T) *s3.Client {
+	t.Helper()
+	client, _ := newFakeS3(t)
+	return client
+}
+
+func newFakeS3(t *testing.T) (*s3.Client, *fakeS3) {
+	t.Helper()
+	fake := &fakeS3{objects: make(map[string]fakeS3Object)}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+	return s3.New(s3.Options{
+		Region:       "us-east-1",
+		Credentials:  aws.AnonymousCredentials{},
+		BaseEndpoint: aws.String(srv.URL),
+		UsePathStyle: true,
+	}), fake
+}
+
+func (f *fakeS3) headCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.heads
+}
+
+func TestS3Storage(t *testing.T) {
+	client := newFakeS3Client(t)
+	ctx := context.Background()
+
+	storage := slackcnr.NewS3Storage(client, "bucket", "slackcnr/channels.json", time.Hour)
+	require.True(t, storage.NeedRefresh(ctx), "object not found should need refresh")
+	_, err := storage.GetByChannelName(ctx, "test")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound)
+
+	require.NoError(t, storage.SetChannels(ctx, []slack.Channel{
+		newTestChannel("C012345678", "test"),
+	}))
+	require.NoError(t, storage.SetChannels(ctx, []slack.Channel{
+		newTestChannel("C023456789", "test2"),
+	}))
+	require.False(t, storage.NeedRefresh(ctx))
+
+	// simulate a cold start
+	restored := slackcnr.NewS3Storage(client, "bucket", "slackcnr/channels.json", time.Hour)
+	require.False(t, restored.NeedRefresh(ctx))
+	channel, err := restored.GetByChannelName(ctx, "test")
+	require.NoError(t, err)
+	require.Equal(t, "C012345678", channel.ID)
+	channel, err = restored.GetByChannelName(ctx, "test2")
+	require.NoError(t, err)
+	require.Equal(t, "C023456789", channel.ID)
+}
+
+func TestS3Storage__NeedRefreshChecksS3OnlyWhenExpired(t *testing.T) {
+	client, fake := newFakeS3(t)
+	ctx := context.Background()
+	start := time.Now()
+	current := start
+	slackcnr.SetNow(t, func() time.Time { return current })
+
+	storage := slackcnr.NewS3Storage(client, "bucket", "slackcnr/channels.json", time.Hour)
+	require.True(t, storage.NeedRefresh(ctx))
+	require.Equal(t, 1, fake.headCount(), "the object is checked while the last set time is unknown")
+
+	require.NoError(t, storage.SetChannels(ctx, []slack.Channel{
+		newTestChannel("C012345678", "test"),
+	}))
+	for i := 0; i < 3; i++ {
+		require.False(t, storage.NeedRefresh(ctx))
+	}
+	require.Equal(t, 1, fake.headCount(), "a fresh cache costs no S3 request")
+
+	current = start.Add(2 * time.Hour)
+	require.True(t, storage.NeedRefresh(ctx))
+	require.Equal(t, 2, fake.headCount(), "the object is checked again once expired locally")
+
+	restored := slackcnr.NewS3Storage(client, "bucket", "slackcnr/channels.json", time.Hour)
+	current = start
+	_, err := restored.GetByChannelName(ctx, "test")
+	require.NoError(t, err)
+	require.False(t, restored.NeedRefresh(ctx))
+	require.Equal(t, 2, fake.headCount(), "the last set time is read on load")
+}
+
+func TestS3Storage__ReloadWhenRewrittenByAnotherProcess(t *testing.T) {
+	client := newFakeS3Client(t)
+	ctx := context.Background()
+	start := time.Now()
+	current := start
+	slackcnr.SetNow(t, func() time.Time { return current })
+
+	a := slackcnr.NewS3Storage(client, "bucket", "slackcnr/channels.json", time.Hour)
+	require.NoError(t, a.SetChannels(ctx, []slack.Channel{
+		newTestChannel("C012345678", "old"),
+	}))
+
+	current = start.Add(2 * time.Hour)
+	b := slackcnr.NewS3Storage(client, "bucket", "slackcnr/channels.json", time.Hour)
+	require.NoError(t, b.ReplaceChannels(ctx, []slack.Channel{
+		newTestChannel("C023456789", "new"),
+	}))
+
+	require.False(t, a.NeedRefresh(ctx), "the object rewritten by another process is fresh")
+	channel, err := a.GetByChannelName(ctx, "new")
+	require.NoError(t, err, "the local copy is loaded again")
+	require.Equal(t, "C023456789", channel.ID)
+	_, err = a.GetByChannelName(ctx, "old")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound, "the channel dropped by the replace is not kept locally")
+
+	require.NoError(t, a.DeleteChannels(ctx, "C023456789"))
+	restored := slackcnr.NewS3Storage(client, "bucket", "slackcnr/channels.json", time.Hour)
+	_, err = restored.GetByChannelName(ctx, "new")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound, "the deleted channel is removed from the object")
+}
@@ -0,0 +1,46 @@
+package slackcnr
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+// WithSeparatorInsensitive makes "-", "_" and spaces equivalent in channel names,
+// so that "team_dev", "team-dev" and "team dev" resolve to the same channel.
+// a channel is also indexed by its name with the separators collapsed to "-", and a name not found as is
+// falls back to that form. the resolved channel keeps its original name,
+// and a channel actually named as queried always wins.
+// it requires the cache storage to implement KeyedStorage, NewChecked returns an error otherwise.
+func WithSeparatorInsensitive() ResolverOption {
+	return func(o *resolverOptions) {
+		o.separatorInsensitive = true
+	}
+}
+
+var separatorReplacer = strings.NewReplacer("_", "-", " ", "-")
+
+// canonicalSeparators returns the name with "_" and spaces replaced by "-".
+func canonicalSeparators(name string) string {
+	return separatorReplacer.Replace(name)
+}
+
+// lookupSeparatorInsensitive resolves the name by its canonical separators.
+// it returns the original error if there is no such channel.
+func (r *Resolver) lookupSeparatorInsensitive(ctx context.Context, channelName string, err error) (*slack.Channel, error) {
+	if !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	canonical := canonicalSeparators(channelName)
+	if canonical == channelName {
+		// the canonical form was already looked up.
+		return nil, err
+	}
+	channel, getErr := r.getByChannelName(ctx, canonical)
+	if getErr != nil {
+		return nil, err
+	}
+	return channel, nil
+}
@@ -0,0 +1,55 @@
+package slackcnr_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolverLookup__SeparatorInsensitive(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C012345678", "team-dev"),
+		newTestChannel("C023456789", "foo_bar"),
+		newTestChannel("C034567890", "x_y"),
+		newTestChannel("C045678901", "x-y"),
+	}, "", nil).Once()
+	r, err := slackcnr.NewChecked(client,
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+		slackcnr.WithSeparatorInsensitive(),
+	)
+	require.NoError(t, err)
+	ctx := context.Background()
+	cases := []struct {
+		query        string
+		expectedID   string
+		expectedName string
+	}{
+		{query: "team_dev", expectedID: "C012345678", expectedName: "team-dev"},
+		{query: "team-dev", expectedID: "C012345678", expectedName: "team-dev"},
+		{query: "team dev", expectedID: "C012345678", expectedName: "team-dev"},
+		{query: "foo-bar", expectedID: "C023456789", expectedName: "foo_bar"},
+		{query: "foo bar", expectedID: "C023456789", expectedName: "foo_bar"},
+		{query: "x_y", expectedID: "C034567890", expectedName: "x_y"},
+		{query: "x-y", expectedID: "C045678901", expectedName: "x-y"},
+	}
+	for _, c := range cases {
+		channel, err := r.Lookup(ctx, c.query)
+		require.NoError(t, err, c.query)
+		require.Equal(t, c.expectedID, channel.ID, c.query)
+		require.Equal(t, c.expectedName, channel.Name, "the original channel is returned")
+	}
+	_, err = r.Lookup(ctx, "team.dev")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound)
+
+	_, err = slackcnr.NewChecked(client,
+		slackcnr.WithCacheStorage(slackcnr.NewLRUStorage(10, 0)),
+		slackcnr.WithSeparatorInsensitive(),
+	)
+	require.Error(t, err)
+}
@@ -0,0 +1,86 @@
+// Package server exposes a slackcnr.Resolver over HTTP, to share one warm cache across many services.
+//
+// Endpoints:
+//
+//	GET /lookup?name=general  returns the channel as JSON. 404 if not found.
+//	GET /stats                returns the resolver statistics as JSON.
+//	GET /healthz              returns 200 if the resolver is healthy, 503 otherwise.
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/mashiike/slackcnr"
+)
+
+// Handler is an http.Handler backed by a single resolver.
+type Handler struct {
+	resolver *slackcnr.Resolver
+	mux      *http.ServeMux
+}
+
+// New creates a new handler for the resolver.
+func New(resolver *slackcnr.Resolver) *Handler {
+	h := &Handler{
+		resolver: resolver,
+		mux:      http.NewServeMux(),
+	}
+	h.mux.HandleFunc("/lookup", h.handleLookup)
+	h.mux.HandleFunc("/stats", h.handleStats)
+	h.mux.HandleFunc("/healthz", h.handleHealthz)
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+	h.mux.ServeHTTP(w, req)
+}
+
+func (h *Handler) handleLookup(w http.ResponseWriter, req *http.Request) {
+	name := req.URL.Query().Get("name")
+	if name == "" {
+		writeError(w, http.StatusBadRequest, errors.New("name is required"))
+		return
+	}
+	channel, err := h.resolver.Lookup(req.Context(), name)
+	if err != nil {
+		if errors.Is(err, slackcnr.ErrNotFound) {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, channel)
+}
+
+func (h *Handler) handleStats(w http.ResponseWriter, req *http.Request) {
+	writeJSON(w, http.StatusOK, h.resolver.Stats())
+}
+
+func (h *Handler) handleHealthz(w http.ResponseWriter, req *http.Request) {
+	if err := h.resolver.Healthy(req.Context()); err != nil {
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
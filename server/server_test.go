@@ -0,0 +1,75 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/mashiike/slackcnr/server"
+	"github.com/mashiike/slackcnr/slackcnrtest"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	resolver := slackcnrtest.NewTestResolver(
+		slackcnrtest.NewChannel("C012345678", "general"),
+	)
+	h := server.New(resolver)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/lookup?name=general", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var channel slack.Channel
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&channel))
+	require.Equal(t, "C012345678", channel.ID)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/lookup?name=unknown", nil))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/lookup", nil))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stats", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var stats slackcnr.Stats
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&stats))
+	require.EqualValues(t, 1, stats.Hits)
+	require.EqualValues(t, 1, stats.Misses)
+}
+
+func TestHandler__APIError(t *testing.T) {
+	resolver := slackcnr.New(&failingClient{}, slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)))
+	h := server.New(resolver)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/lookup?name=general", nil))
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/lookup?name=general", nil))
+	require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+type failingClient struct{}
+
+func (failingClient) GetConversationsForUserContext(ctx context.Context, params *slack.GetConversationsForUserParameters) ([]slack.Channel, string, error) {
+	return nil, "", slack.SlackErrorResponse{Err: "internal_error"}
+}
+
+func (failingClient) GetConversationsContext(ctx context.Context, params *slack.GetConversationsParameters) ([]slack.Channel, string, error) {
+	return nil, "", slack.SlackErrorResponse{Err: "internal_error"}
+}
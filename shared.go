@@ -0,0 +1,123 @@
+package slackcnr
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+// WithIndexSharedChannelPrefixes makes externally shared channels resolvable with and without the team prefix.
+// the name of a shared channel may carry a prefix like "partner-general", depending on which team it is viewed from.
+// a shared channel named "partner-general" is also indexed as "general",
+// and looking up "partner-general" falls back to a shared channel named "general".
+// the prefix is the part before the first "-". an unprefixed key never shadows a channel actually named so.
+// it requires the cache storage to implement KeyedStorage, NewChecked returns an error otherwise.
+func WithIndexSharedChannelPrefixes() ResolverOption {
+	return func(o *resolverOptions) {
+		o.indexSharedPrefixes = true
+	}
+}
+
+// lookupKeyFunc returns the key func applied to the cache storage,
+// combining WithKeyFunc, WithIndexSharedChannelPrefixes and WithSeparatorInsensitive.
+func (o *resolverOptions) lookupKeyFunc() func(channel *slack.Channel) []string {
+	if !o.indexSharedPrefixes && !o.separatorInsensitive && !o.normalizesNames() {
+		return o.keyFunc
+	}
+	keyFunc := o.keyFunc
+	indexSharedPrefixes := o.indexSharedPrefixes
+	separatorInsensitive := o.separatorInsensitive
+	normalize := o.normalizeName
+	return func(channel *slack.Channel) []string {
+		var keys []string
+		if keyFunc != nil {
+			keys = keyFunc(channel)
+		}
+		if indexSharedPrefixes {
+			if name, ok := unprefixedSharedName(channel); ok {
+				keys = append(keys, normalize(name))
+			}
+		}
+		// lookups query the normalized name, so the channel is indexed by it too.
+		name := normalize(channel.Name)
+		if name != channel.Name {
+			keys = append(keys, name)
+		}
+		if separatorInsensitive {
+			keys = append(keys, canonicalSeparators(name))
+		}
+		return keys
+	}
+}
+
+// isExternallyShared reports whether the channel is shared with a team outside of the organization.
+func isExternallyShared(channel *slack.Channel) bool {
+	if channel.IsExtShared || channel.IsPendingExtShared {
+		return true
+	}
+	if !channel.IsShared || len(channel.InternalTeamIDs) == 0 {
+		return false
+	}
+	for _, id := range channel.SharedTeamIDs {
+		if !containsString(channel.InternalTeamIDs, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// unprefixedSharedName returns the name of an externally shared channel without the team prefix.
+func unprefixedSharedName(channel *slack.Channel) (string, bool) {
+	if !isExternallyShared(channel) {
+		return "", false
+	}
+	return trimSharedPrefix(channel.Name)
+}
+
+func trimSharedPrefix(name string) (string, bool) {
+	_, rest, ok := strings.Cut(name, "-")
+	if !ok || rest == "" {
+		return "", false
+	}
+	return rest, true
+}
+
+// lookupSharedPrefixed resolves a prefixed name to the externally shared channel stored without the prefix.
+// it returns the original error if there is no such channel.
+func (r *Resolver) lookupSharedPrefixed(ctx context.Context, channelName string, err error) (*slack.Channel, error) {
+	if !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	name, ok := trimSharedPrefix(channelName)
+	if !ok {
+		return nil, err
+	}
+	channel, getErr := r.getByChannelName(ctx, name)
+	if getErr != nil || !isExternallyShared(channel) {
+		return nil, err
+	}
+	return channel, nil
+}
+
+// LookupExternal finds a channel by the team it is shared with and its name,
+// such as to tell apart same-named connected channels of different teams on Enterprise Grid.
+// a channel matches if teamID is one of its shared or connected team IDs.
+// it returns ErrNotFound if no channel named channelName is associated with the team, or if it is not in WithAllowlist.
+// it requires the cache storage to implement MultiNameStorage, otherwise it returns ErrLookupAllNotSupported.
+func (r *Resolver) LookupExternal(ctx context.Context, teamID, channelName string) (*slack.Channel, error) {
+	channels, err := r.LookupAll(ctx, channelName)
+	if err != nil {
+		return nil, err
+	}
+	for i := range channels {
+		if !r.allowsChannel(&channels[i]) {
+			continue
+		}
+		if containsString(channels[i].SharedTeamIDs, teamID) || containsString(channels[i].ConnectedTeamIDs, teamID) {
+			return &channels[i], nil
+		}
+	}
+	return nil, ErrNotFound
+}
@@ -0,0 +1,118 @@
+package slackcnr_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const sharedChannelsPayload = `[
+	{"id": "C1", "name": "partner-general", "is_channel": true, "is_shared": true, "is_ext_shared": true, "shared_team_ids": ["T1", "T9"], "internal_team_ids": ["T1"]},
+	{"id": "C2", "name": "alerts", "is_channel": true, "is_shared": true, "is_ext_shared": true, "shared_team_ids": ["T1", "T9"], "internal_team_ids": ["T1"]},
+	{"id": "C3", "name": "general", "is_channel": true},
+	{"id": "C4", "name": "team-backend", "is_channel": true}
+]`
+
+func TestResolver__IndexSharedChannelPrefixes(t *testing.T) {
+	var channels []slack.Channel
+	require.NoError(t, json.Unmarshal([]byte(sharedChannelsPayload), &channels))
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return(channels, "", nil).Once()
+	resolver, err := slackcnr.NewChecked(
+		client,
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+		slackcnr.WithIndexSharedChannelPrefixes(),
+	)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	cases := []struct {
+		name     string
+		expected string
+	}{
+		{name: "partner-general", expected: "C1"},
+		// the unprefixed form does not shadow a channel actually named so.
+		{name: "general", expected: "C3"},
+		{name: "alerts", expected: "C2"},
+		{name: "partner-alerts", expected: "C2"},
+		{name: "team-backend", expected: "C4"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			channel, err := resolver.Lookup(ctx, c.name)
+			require.NoError(t, err)
+			require.Equal(t, c.expected, channel.ID)
+		})
+	}
+	// only externally shared channels are indexed without the prefix.
+	_, err = resolver.Lookup(ctx, "backend")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound)
+	_, err = resolver.Lookup(ctx, "partner-team-backend")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound)
+}
+
+func TestNewChecked__IndexSharedChannelPrefixesRequiresKeyedStorage(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	_, err := slackcnr.NewChecked(
+		client,
+		slackcnr.WithCacheStorage(slackcnr.NewLRUStorage(10, 0)),
+		slackcnr.WithIndexSharedChannelPrefixes(),
+	)
+	require.Error(t, err)
+}
+
+func TestResolverLookupExternal(t *testing.T) {
+	var channels []slack.Channel
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"id": "C1", "name": "partner-support", "is_channel": true, "is_shared": true, "is_ext_shared": true, "shared_team_ids": ["T1", "T8"], "internal_team_ids": ["T1"]},
+		{"id": "C2", "name": "partner-support", "is_channel": true, "is_shared": true, "is_ext_shared": true, "shared_team_ids": ["T1"], "connected_team_ids": ["T9"], "internal_team_ids": ["T1"]},
+		{"id": "C3", "name": "general", "is_channel": true}
+	]`), &channels))
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return(channels, "", nil).Once()
+	r := slackcnr.New(client, slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)))
+	ctx := context.Background()
+
+	channel, err := r.LookupExternal(ctx, "T8", "partner-support")
+	require.NoError(t, err)
+	require.Equal(t, "C1", channel.ID)
+	channel, err = r.LookupExternal(ctx, "T9", "partner-support")
+	require.NoError(t, err)
+	require.Equal(t, "C2", channel.ID)
+
+	_, err = r.LookupExternal(ctx, "T7", "partner-support")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound)
+	_, err = r.LookupExternal(ctx, "T8", "general")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound)
+	_, err = r.LookupExternal(ctx, "T8", "unknown")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound)
+}
+
+func TestResolverLookupExternal__Allowlist(t *testing.T) {
+	var channels []slack.Channel
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"id": "C1", "name": "partner-support", "is_channel": true, "is_shared": true, "is_ext_shared": true, "shared_team_ids": ["T1", "T8"], "internal_team_ids": ["T1"]},
+		{"id": "C2", "name": "partner-sales", "is_channel": true, "is_shared": true, "is_ext_shared": true, "shared_team_ids": ["T1", "T8"], "internal_team_ids": ["T1"]}
+	]`), &channels))
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return(channels, "", nil).Once()
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+		slackcnr.WithAllowlist("partner-support"),
+	)
+	ctx := context.Background()
+
+	channel, err := r.LookupExternal(ctx, "T8", "partner-support")
+	require.NoError(t, err)
+	require.Equal(t, "C1", channel.ID)
+	_, err = r.LookupExternal(ctx, "T8", "partner-sales")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound, "a shared channel not in the allowlist is not found")
+}
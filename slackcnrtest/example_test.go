@@ -0,0 +1,22 @@
+package slackcnrtest_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mashiike/slackcnr/slackcnrtest"
+)
+
+func ExampleNewTestResolver() {
+	resolver := slackcnrtest.NewTestResolver(
+		slackcnrtest.NewChannel("C012345678", "general"),
+		slackcnrtest.NewChannel("C023456789", "random"),
+	)
+	channel, err := resolver.Lookup(context.Background(), "random")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(channel.ID)
+	// Output: C023456789
+}
@@ -0,0 +1,110 @@
+// Package slackcnrtest provides fakes for testing code that uses slackcnr.
+package slackcnrtest
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+)
+
+// FakeClient is a slackcnr.SlackClient that serves seeded channels without calling the Slack API.
+// both users.conversations and conversations.list return all seeded channels, paginated by the limit parameter.
+type FakeClient struct {
+	mu       sync.Mutex
+	channels []slack.Channel
+}
+
+var _ slackcnr.ConversationInfoClient = (*FakeClient)(nil)
+
+// NewFakeClient creates a new fake client seeded with the channels.
+func NewFakeClient(channels ...slack.Channel) *FakeClient {
+	return &FakeClient{
+		channels: append([]slack.Channel(nil), channels...),
+	}
+}
+
+// AddChannels adds channels to the fake client. they are visible after the next refresh.
+func (c *FakeClient) AddChannels(channels ...slack.Channel) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.channels = append(c.channels, channels...)
+}
+
+func (c *FakeClient) GetConversationsForUserContext(ctx context.Context, params *slack.GetConversationsForUserParameters) ([]slack.Channel, string, error) {
+	return c.page(ctx, params.Cursor, params.Limit, params.ExcludeArchived)
+}
+
+func (c *FakeClient) GetConversationsContext(ctx context.Context, params *slack.GetConversationsParameters) ([]slack.Channel, string, error) {
+	return c.page(ctx, params.Cursor, params.Limit, params.ExcludeArchived)
+}
+
+func (c *FakeClient) GetConversationInfoContext(ctx context.Context, input *slack.GetConversationInfoInput) (*slack.Channel, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, channel := range c.channels {
+		if channel.ID == input.ChannelID {
+			return &channel, nil
+		}
+	}
+	return nil, slack.SlackErrorResponse{Err: "channel_not_found"}
+}
+
+func (c *FakeClient) page(ctx context.Context, cursor string, limit int, excludeArchived bool) ([]slack.Channel, string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, "", err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	channels := make([]slack.Channel, 0, len(c.channels))
+	for _, channel := range c.channels {
+		if excludeArchived && channel.IsArchived {
+			continue
+		}
+		channels = append(channels, channel)
+	}
+	start := 0
+	if cursor != "" {
+		var err error
+		start, err = strconv.Atoi(cursor)
+		if err != nil || start < 0 || start > len(channels) {
+			return nil, "", slack.SlackErrorResponse{Err: "invalid_cursor"}
+		}
+	}
+	end := len(channels)
+	if limit > 0 && start+limit < end {
+		end = start + limit
+	}
+	var nextCursor string
+	if end < len(channels) {
+		nextCursor = strconv.Itoa(end)
+	}
+	return channels[start:end], nextCursor, nil
+}
+
+// NewChannel creates a channel with the ID and name, for seeding fakes.
+func NewChannel(id, name string) slack.Channel {
+	return slack.Channel{
+		GroupConversation: slack.GroupConversation{
+			Conversation: slack.Conversation{
+				ID: id,
+			},
+			Name: name,
+		},
+		IsChannel: true,
+		IsMember:  true,
+	}
+}
+
+// NewTestResolver creates a resolver backed by a FakeClient seeded with the channels, and a non-expiring in-memory storage.
+func NewTestResolver(channels ...slack.Channel) *slackcnr.Resolver {
+	return slackcnr.New(
+		NewFakeClient(channels...),
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+	)
+}
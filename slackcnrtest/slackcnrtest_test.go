@@ -0,0 +1,33 @@
+package slackcnrtest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/mashiike/slackcnr/slackcnrtest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeClient__Pagination(t *testing.T) {
+	client := slackcnrtest.NewFakeClient(
+		slackcnrtest.NewChannel("C1", "one"),
+		slackcnrtest.NewChannel("C2", "two"),
+		slackcnrtest.NewChannel("C3", "three"),
+	)
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+		slackcnr.WithBatchSize(2),
+	)
+	ctx := context.Background()
+	channel, err := r.Lookup(ctx, "three")
+	require.NoError(t, err)
+	require.Equal(t, "C3", channel.ID)
+	require.EqualValues(t, 3, r.Stats().CachedChannels)
+
+	client.AddChannels(slackcnrtest.NewChannel("C4", "four"))
+	require.NoError(t, r.Refresh(ctx))
+	channel, err = r.Lookup(ctx, "four")
+	require.NoError(t, err)
+	require.Equal(t, "C4", channel.ID)
+}
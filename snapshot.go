@@ -0,0 +1,147 @@
+package slackcnr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// Snapshot is a named point-in-time copy of the cached channels.
+type Snapshot struct {
+	Version   string          `json:"version"`
+	CreatedAt time.Time       `json:"created_at"`
+	Channels  []slack.Channel `json:"channels"`
+}
+
+// SnapshotStore saves and loads snapshots by version.
+type SnapshotStore interface {
+	SaveSnapshot(ctx context.Context, snapshot *Snapshot) error
+	// LoadSnapshot returns ErrSnapshotNotFound if there is no snapshot of the version.
+	LoadSnapshot(ctx context.Context, version string) (*Snapshot, error)
+}
+
+var (
+	// ErrSnapshotNotFound is returned when there is no snapshot of the version.
+	ErrSnapshotNotFound = errors.New("snapshot not found")
+	// ErrSnapshotStoreNotConfigured is returned when the resolver has no snapshot store set by WithSnapshotStore.
+	ErrSnapshotStoreNotConfigured = errors.New("snapshot store is not configured")
+)
+
+// WithSnapshotStore sets the store for ExportSnapshot and Pin.
+func WithSnapshotStore(store SnapshotStore) ResolverOption {
+	return func(o *resolverOptions) {
+		o.snapshotStore = store
+	}
+}
+
+// FileSnapshotStore is a SnapshotStore that keeps each snapshot as a JSON file named <version>.json in a directory.
+type FileSnapshotStore struct {
+	dir string
+}
+
+var _ SnapshotStore = (*FileSnapshotStore)(nil)
+
+// NewFileSnapshotStore creates a new file snapshot store in the directory.
+func NewFileSnapshotStore(dir string) *FileSnapshotStore {
+	return &FileSnapshotStore{dir: dir}
+}
+
+func (s *FileSnapshotStore) path(version string) (string, error) {
+	if version == "" || version == "." || version == ".." || strings.ContainsAny(version, `/\`) {
+		return "", fmt.Errorf("invalid snapshot version %q", version)
+	}
+	return filepath.Join(s.dir, version+".json"), nil
+}
+
+func (s *FileSnapshotStore) SaveSnapshot(ctx context.Context, snapshot *Snapshot) error {
+	path, err := s.path(snapshot.Version)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, body, 0o644)
+}
+
+func (s *FileSnapshotStore) LoadSnapshot(ctx context.Context, version string) (*Snapshot, error) {
+	path, err := s.path(version)
+	if err != nil {
+		return nil, err
+	}
+	body, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrSnapshotNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(body, &snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// ExportSnapshot saves the cached channels as a snapshot of the version to the store set by WithSnapshotStore.
+// the cache storage must implement ListableStorage.
+func (r *Resolver) ExportSnapshot(ctx context.Context, version string) error {
+	ctx = ensureContext(ctx)
+	if r.opts.snapshotStore == nil {
+		return ErrSnapshotStoreNotConfigured
+	}
+	channels, err := r.List(ctx)
+	if err != nil {
+		return err
+	}
+	return r.opts.snapshotStore.SaveSnapshot(ctx, &Snapshot{
+		Version:   version,
+		CreatedAt: time.Now(),
+		Channels:  channels,
+	})
+}
+
+// Pin loads the snapshot of the version and serves all lookups from it, so that they are deterministic.
+// a pinned resolver ignores the cache expiry and never refreshes nor calls Slack API, until Unpin is called.
+func (r *Resolver) Pin(ctx context.Context, version string) error {
+	ctx = ensureContext(ctx)
+	if r.opts.snapshotStore == nil {
+		return ErrSnapshotStoreNotConfigured
+	}
+	snapshot, err := r.opts.snapshotStore.LoadSnapshot(ctx, version)
+	if err != nil {
+		return err
+	}
+	pinned := NewInMemoryStorage(0)
+	r.configureStorage(pinned)
+	if err := pinned.SetChannels(ctx, snapshot.Channels); err != nil {
+		return err
+	}
+	r.pinned.Store(pinned)
+	return nil
+}
+
+// Unpin restores the live cache storage after Pin.
+func (r *Resolver) Unpin() {
+	r.pinned.Store(nil)
+}
+
+// readStorage returns the storage lookups read from, the pinned snapshot if any.
+func (r *Resolver) readStorage() Storage {
+	if pinned := r.pinned.Load(); pinned != nil {
+		return pinned
+	}
+	return r.cacheStorage()
+}
+
+func (r *Resolver) isPinned() bool {
+	return r.pinned.Load() != nil
+}
@@ -0,0 +1,85 @@
+package slackcnr_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolver__PinSnapshot(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C1", "general"),
+	}, "", nil).Once()
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C1", "renamed"),
+		newTestChannel("C2", "general"),
+	}, "", nil).Once()
+	store := slackcnr.NewFileSnapshotStore(t.TempDir())
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+		slackcnr.WithSnapshotStore(store),
+	)
+	ctx := context.Background()
+
+	require.NoError(t, r.Refresh(ctx))
+	require.NoError(t, r.ExportSnapshot(ctx, "v1"))
+	require.NoError(t, r.Refresh(ctx))
+	channel, err := r.Lookup(ctx, "general")
+	require.NoError(t, err)
+	require.Equal(t, "C2", channel.ID)
+
+	require.NoError(t, r.Pin(ctx, "v1"))
+	channel, err = r.Lookup(ctx, "general")
+	require.NoError(t, err)
+	require.Equal(t, "C1", channel.ID, "lookups are served from the pinned snapshot")
+	_, err = r.Lookup(ctx, "renamed")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound)
+	require.NoError(t, r.Refresh(ctx), "refresh is skipped while pinned")
+
+	r.Unpin()
+	channel, err = r.Lookup(ctx, "general")
+	require.NoError(t, err)
+	require.Equal(t, "C2", channel.ID)
+	client.AssertNumberOfCalls(t, "GetConversationsForUserContext", 2)
+}
+
+func TestResolver__PinErrors(t *testing.T) {
+	ctx := context.Background()
+	r := slackcnr.New(&mockSlackClient{t: t})
+	require.ErrorIs(t, r.Pin(ctx, "v1"), slackcnr.ErrSnapshotStoreNotConfigured)
+
+	r = slackcnr.New(&mockSlackClient{t: t}, slackcnr.WithSnapshotStore(slackcnr.NewFileSnapshotStore(t.TempDir())))
+	require.ErrorIs(t, r.Pin(ctx, "missing"), slackcnr.ErrSnapshotNotFound)
+	require.Error(t, r.Pin(ctx, "../escape"))
+}
+
+func TestResolver__PinCallsNoAPIOnMiss(t *testing.T) {
+	client := &mockConversationInfoClient{mockSlackClient{t: t}}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C1", "general"),
+	}, "", nil).Once()
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+		slackcnr.WithSnapshotStore(slackcnr.NewFileSnapshotStore(t.TempDir())),
+	)
+	ctx := context.Background()
+	require.NoError(t, r.Refresh(ctx))
+	require.NoError(t, r.ExportSnapshot(ctx, "v1"))
+	require.NoError(t, r.Pin(ctx, "v1"))
+
+	_, err := r.LookupOrFetch(ctx, "new-project")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound, "a miss is not scanned while pinned")
+	_, _, err = r.FetchPage(ctx, slackcnr.SourcePublicChannels, "")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound)
+	_, err = r.FetchAndCacheByID(ctx, "C2")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound)
+	client.AssertNotCalled(t, "GetConversationsContext", mock.Anything, mock.Anything)
+	client.AssertNotCalled(t, "GetConversationInfoContext", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,76 @@
+package slackcnr
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+)
+
+// SourceAdminConversations is the source of channels fetched with admin.conversations.search API, see WithAdminEnumeration.
+const SourceAdminConversations Source = Source(sourceAdminConversations)
+
+// ErrSourceNotRecorded is returned by SourceOf when WithRecordSource is not set.
+var ErrSourceNotRecorded = errors.New("channel sources are not recorded, WithRecordSource is required")
+
+// WithRecordSource records which API each cached channel was found by in a refresh, returned by SourceOf.
+// it is meant for diagnostics, and does not change lookup results.
+func WithRecordSource() ResolverOption {
+	return func(o *resolverOptions) {
+		o.recordSource = true
+	}
+}
+
+// SourceOf finds a channel by name like Lookup, and returns the API the last refresh found it by.
+// a channel found by several passes reports the first one, users.conversations before conversations.list.
+// it is empty for a channel not stored by a refresh, such as one added by UpdateChannel.
+func (r *Resolver) SourceOf(ctx context.Context, channelName string) (Source, error) {
+	if !r.opts.recordSource {
+		return "", ErrSourceNotRecorded
+	}
+	channel, err := r.Lookup(ctx, channelName)
+	if err != nil {
+		return "", err
+	}
+	return r.sources.get(channel.ID), nil
+}
+
+// channelSources maps channel IDs to the source they were found by.
+type channelSources struct {
+	mu      sync.RWMutex
+	sources map[string]Source
+}
+
+func (s *channelSources) get(id string) Source {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sources[id]
+}
+
+// record records the sources of the channels of a refresh. unless merge, the previous sources are forgotten.
+func (s *channelSources) record(tasks []refreshTask, results []refreshResult, merge bool) {
+	sources := make(map[string]Source)
+	for i, result := range results {
+		source := publicSource(tasks[i].source)
+		for _, channel := range result.channels {
+			if _, ok := sources[channel.ID]; !ok {
+				sources[channel.ID] = source
+			}
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if merge && s.sources != nil {
+		for id, source := range sources {
+			s.sources[id] = source
+		}
+		return
+	}
+	s.sources = sources
+}
+
+// publicSource returns the exported source of a refresh pass, without the team ID of a per-team admin pass.
+func publicSource(source refreshSource) Source {
+	name, _, _ := strings.Cut(string(source), ":")
+	return Source(name)
+}
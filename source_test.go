@@ -0,0 +1,49 @@
+package slackcnr_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolverSourceOf(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	member := newTestChannel("C012345678", "member")
+	member.IsMember = true
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{member}, "", nil).Once()
+	client.On("GetConversationsContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		member,
+		newTestChannel("C023456789", "public-only"),
+	}, "", nil).Once()
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+		slackcnr.WithSearchPublicChannels(),
+		slackcnr.WithRecordSource(),
+	)
+	ctx := context.Background()
+
+	source, err := r.SourceOf(ctx, "member")
+	require.NoError(t, err)
+	require.Equal(t, slackcnr.SourceUserConversations, source)
+	source, err = r.SourceOf(ctx, "public-only")
+	require.NoError(t, err)
+	require.Equal(t, slackcnr.SourcePublicChannels, source)
+	_, err = r.SourceOf(ctx, "unknown")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound)
+
+	require.NoError(t, r.UpdateChannel(ctx, newTestChannel("C034567890", "added")))
+	source, err = r.SourceOf(ctx, "added")
+	require.NoError(t, err)
+	require.Empty(t, source)
+}
+
+func TestResolverSourceOf__NotRecorded(t *testing.T) {
+	r := slackcnr.New(&mockSlackClient{t: t})
+	_, err := r.SourceOf(context.Background(), "general")
+	require.ErrorIs(t, err, slackcnr.ErrSourceNotRecorded)
+}
@@ -0,0 +1,81 @@
+package slackcnr
+
+import (
+	"expvar"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Stats is a snapshot of resolver statistics.
+type Stats struct {
+	Hits            int64     `json:"hits"`
+	Misses          int64     `json:"misses"`
+	Refreshes       int64     `json:"refreshes"`
+	LastRefreshTime time.Time `json:"last_refresh_time"`
+	CachedChannels  int64     `json:"cached_channels"`
+	// Degraded is the number of times the stale cache was served because a refresh was skipped.
+	Degraded int64 `json:"degraded"`
+}
+
+type resolverStats struct {
+	hits            atomic.Int64
+	misses          atomic.Int64
+	refreshes       atomic.Int64
+	lastRefreshTime atomic.Int64
+	cachedChannels  atomic.Int64
+	degraded        atomic.Int64
+}
+
+func (s *resolverStats) snapshot() Stats {
+	stats := Stats{
+		Hits:           s.hits.Load(),
+		Misses:         s.misses.Load(),
+		Refreshes:      s.refreshes.Load(),
+		CachedChannels: s.cachedChannels.Load(),
+		Degraded:       s.degraded.Load(),
+	}
+	if t := s.lastRefreshTime.Load(); t != 0 {
+		stats.LastRefreshTime = time.Unix(0, t)
+	}
+	return stats
+}
+
+func (s *resolverStats) recordRefresh(cached int64) {
+	s.cachedChannels.Store(cached)
+	s.lastRefreshTime.Store(time.Now().UnixNano())
+	s.refreshes.Add(1)
+}
+
+// Stats returns a snapshot of the resolver statistics.
+func (r *Resolver) Stats() Stats {
+	return r.stats.snapshot()
+}
+
+// WithExpvar publishes the resolver statistics to expvar under the given name.
+// if a variable with the same name is already published, it is not replaced:
+// New logs a warning and NewChecked returns an error.
+func WithExpvar(name string) ResolverOption {
+	return func(o *resolverOptions) {
+		o.expvarName = name
+	}
+}
+
+// expvarMu guards checking and publishing an expvar name, since expvar.Publish panics on a duplicate name.
+var expvarMu sync.Mutex
+
+func (r *Resolver) publishExpvar() error {
+	if r.opts.expvarName == "" {
+		return nil
+	}
+	expvarMu.Lock()
+	defer expvarMu.Unlock()
+	if expvar.Get(r.opts.expvarName) != nil {
+		return fmt.Errorf("expvar %q is already published", r.opts.expvarName)
+	}
+	expvar.Publish(r.opts.expvarName, expvar.Func(func() any {
+		return r.Stats()
+	}))
+	return nil
+}
@@ -0,0 +1,81 @@
+package slackcnr_test
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolverStats__Expvar(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		{
+			GroupConversation: slack.GroupConversation{
+				Conversation: slack.Conversation{
+					ID: "C012345678",
+				},
+				Name: "test",
+			},
+		},
+	}, "", nil).Times(1)
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+		slackcnr.WithExpvar("slackcnr_test_stats"),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	_, err := r.Lookup(ctx, "test")
+	require.NoError(t, err)
+	_, err = r.Lookup(ctx, "unknown")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound)
+
+	v := expvar.Get("slackcnr_test_stats")
+	require.NotNil(t, v)
+	var published slackcnr.Stats
+	require.NoError(t, json.Unmarshal([]byte(v.String()), &published))
+	require.EqualValues(t, 1, published.Hits)
+	require.EqualValues(t, 1, published.Misses)
+	require.EqualValues(t, 1, published.Refreshes)
+	require.EqualValues(t, 1, published.CachedChannels)
+	require.False(t, published.LastRefreshTime.IsZero())
+	require.Equal(t, r.Stats().Hits, published.Hits)
+}
+
+func TestNewChecked__ExpvarNameInUse(t *testing.T) {
+	const name = "slackcnr_test_stats_duplicated"
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slackcnr.New(&mockSlackClient{t: t}, slackcnr.WithExpvar(name))
+		}()
+	}
+	wg.Wait()
+	require.NotNil(t, expvar.Get(name))
+
+	storage := slackcnr.NewInMemoryStorage(0)
+	_, err := slackcnr.NewChecked(&mockSlackClient{t: t},
+		slackcnr.WithCacheStorage(storage),
+		slackcnr.WithMaxEntries(1, slackcnr.EvictOldest),
+		slackcnr.WithExpvar(name),
+	)
+	require.Error(t, err, "a duplicate name is reported")
+	ctx := context.Background()
+	require.NoError(t, storage.SetChannels(ctx, []slack.Channel{
+		newTestChannel("C012345678", "general"),
+		newTestChannel("C023456789", "random"),
+	}))
+	channels, err := storage.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, channels, 2, "the storage is not configured on error")
+}
@@ -3,8 +3,12 @@ package slackcnr
 import (
 	"context"
 	"errors"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unsafe"
 
 	"github.com/slack-go/slack"
 )
@@ -12,18 +16,52 @@ import (
 var ErrNotFound = errors.New("channel not found")
 
 // Storage defines the interface for caching slack channels.
+// implementations must not share internal state with callers:
+// reads return copies, and channels passed to SetChannels are copied before being stored,
+// so that mutating them never affects the cache.
 type Storage interface {
 	SetChannels(ctx context.Context, channels []slack.Channel) error
 	GetByChannelName(ctx context.Context, channelName string) (*slack.Channel, error)
 	NeedRefresh(ctx context.Context) bool
 }
 
+// SearchableStorage is an optional interface for storages that support substring search by channel name.
+type SearchableStorage interface {
+	Storage
+	// SearchContains returns channels whose name contains substr, case-insensitively, sorted by name.
+	// if limit is greater than 0, at most limit channels are returned.
+	SearchContains(ctx context.Context, substr string, limit int) ([]slack.Channel, error)
+}
+
+var (
+	_ SearchableStorage      = (*InMemoryStorage)(nil)
+	_ SyncStateStorage       = (*InMemoryStorage)(nil)
+	_ ChannelPriorityStorage = (*InMemoryStorage)(nil)
+	_ SizedStorage           = (*InMemoryStorage)(nil)
+	_ KeyedStorage           = (*InMemoryStorage)(nil)
+	_ IDStorage              = (*InMemoryStorage)(nil)
+	_ BackfillStorage        = (*InMemoryStorage)(nil)
+	_ ResettableStorage      = (*InMemoryStorage)(nil)
+)
+
 type InMemoryStorage struct {
 	mu             sync.RWMutex
 	channels       map[string]slack.Channel
 	namesById      map[string]string
 	lastSetTime    time.Time
 	expredDuration time.Duration
+	syncState      SyncState
+	prefer         func(a, b *slack.Channel) bool
+	keyFunc        func(channel *slack.Channel) []string
+	keysById       map[string][]string
+	idsByName      map[string][]string
+	// maxEntries, eviction, ages and seq are set by SetMaxEntries. ages is nil without max entries.
+	maxEntries int
+	eviction   EvictionPolicy
+	ages       map[string]*entryAge
+	seq        atomic.Int64
+	// epoch is the number of times the channels were replaced by ReplaceChannels.
+	epoch uint64
 }
 
 // NewInMemoryStorage creates a new in-memory storage. if expredDuration is 0, it never expires.
@@ -32,6 +70,8 @@ func NewInMemoryStorage(expredDuration time.Duration) *InMemoryStorage {
 		expredDuration: expredDuration,
 		channels:       make(map[string]slack.Channel),
 		namesById:      make(map[string]string),
+		keysById:       make(map[string][]string),
+		idsByName:      make(map[string][]string),
 	}
 }
 
@@ -39,16 +79,147 @@ func (s *InMemoryStorage) SetChannels(ctx context.Context, channels []slack.Chan
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	s.set(channels)
+	s.lastSetTime = now()
+	return nil
+}
+
+// AddChannels stores the channels like SetChannels, but does not reset the expiry of the cache.
+// it is used to backfill single channels between refreshes.
+func (s *InMemoryStorage) AddChannels(ctx context.Context, channels []slack.Channel) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.set(channels)
+	return nil
+}
+
+func (s *InMemoryStorage) set(channels []slack.Channel) {
+	s.grow(len(channels))
+	var renamedFrom []string
 	for _, channel := range channels {
-		s.channels[channel.ID] = channel
-		s.namesById[channel.Name] = channel.ID
+		keys := s.keys(&channel)
+		// keys no longer returned, such as the previous name of a renamed channel, no longer point to the channel.
+		s.unindex(channel.ID, keys)
+		if prev, ok := s.channels[channel.ID]; ok && prev.Name != channel.Name {
+			s.removeNameID(prev.Name, channel.ID)
+			renamedFrom = append(renamedFrom, prev.Name)
+		}
+		if !containsString(s.idsByName[channel.Name], channel.ID) {
+			s.idsByName[channel.Name] = append(s.idsByName[channel.Name], channel.ID)
+		}
+		s.channels[channel.ID] = cloneChannel(channel)
+		indexed := make([]string, 0, len(keys))
+		for _, key := range keys {
+			if s.keepCurrent(key, &channel) {
+				continue
+			}
+			s.namesById[key] = channel.ID
+			indexed = append(indexed, key)
+		}
+		s.keysById[channel.ID] = indexed
+		s.stored(channel.ID)
+	}
+	for _, name := range renamedFrom {
+		s.promote(name)
 	}
+	if s.prefer != nil {
+		// the owner of a name is decided after the whole batch, since the channels compared may be written later in it.
+		for _, channel := range channels {
+			s.reselect(channel.Name)
+		}
+	}
+	s.evict()
+}
 
-	s.lastSetTime = time.Now()
-	return nil
+// grow pre-sizes the empty maps for n channels, to avoid rehashing on a cold refresh of a large workspace.
+func (s *InMemoryStorage) grow(n int) {
+	if len(s.channels) != 0 || n == 0 {
+		return
+	}
+	s.channels = make(map[string]slack.Channel, n)
+	s.namesById = make(map[string]string, n)
+	s.keysById = make(map[string][]string, n)
+	s.idsByName = make(map[string][]string, n)
+}
+
+// promote points the name to another channel sharing it, when the channel it pointed to was renamed or deleted.
+func (s *InMemoryStorage) promote(name string) {
+	if _, ok := s.namesById[name]; ok {
+		return
+	}
+	var best *slack.Channel
+	for _, id := range s.idsByName[name] {
+		channel, ok := s.channels[id]
+		if !ok || channel.Name != name {
+			continue
+		}
+		if best == nil || (s.prefer != nil && s.prefer(&channel, best)) {
+			best = &channel
+		}
+	}
+	if best == nil {
+		return
+	}
+	s.namesById[name] = best.ID
+	s.keysById[best.ID] = append(s.keysById[best.ID], name)
+}
+
+// reselect points the name to the preferred channel among the channels named so.
+func (s *InMemoryStorage) reselect(name string) {
+	var best *slack.Channel
+	for _, id := range s.idsByName[name] {
+		channel, ok := s.channels[id]
+		if !ok || channel.Name != name {
+			continue
+		}
+		if best == nil || s.prefer(&channel, best) {
+			best = &channel
+		}
+	}
+	if best == nil || s.namesById[name] == best.ID {
+		return
+	}
+	// the key left in keysById of the previous owner is ignored by unindex, since the name no longer points to it.
+	s.namesById[name] = best.ID
+	if !containsString(s.keysById[best.ID], name) {
+		s.keysById[best.ID] = append(s.keysById[best.ID], name)
+	}
+}
+
+// removeNameID removes the channel ID from the IDs sharing the name.
+func (s *InMemoryStorage) removeNameID(name, id string) {
+	ids := s.idsByName[name]
+	for i := range ids {
+		if ids[i] != id {
+			continue
+		}
+		ids = append(ids[:i:i], ids[i+1:]...)
+		break
+	}
+	if len(ids) == 0 {
+		delete(s.idsByName, name)
+		return
+	}
+	s.idsByName[name] = ids
 }
 
 func (s *InMemoryStorage) GetByChannelName(ctx context.Context, channelName string) (*slack.Channel, error) {
+	return s.getByChannelName(channelName, true)
+}
+
+// GetByChannelID returns the channel with the given ID.
+func (s *InMemoryStorage) GetByChannelID(ctx context.Context, channelID string) (*slack.Channel, error) {
+	return s.getByChannelID(channelID, true)
+}
+
+// peekByChannelName returns the channel like GetByChannelName, without recording it as used for EvictLRU,
+// for reads that are not lookups such as DumpIndex.
+func (s *InMemoryStorage) peekByChannelName(channelName string) (*slack.Channel, error) {
+	return s.getByChannelName(channelName, false)
+}
+
+func (s *InMemoryStorage) getByChannelName(channelName string, use bool) (*slack.Channel, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
@@ -56,12 +227,26 @@ func (s *InMemoryStorage) GetByChannelName(ctx context.Context, channelName stri
 	if !ok {
 		return nil, ErrNotFound
 	}
+	return s.getByChannelIDLocked(id, use)
+}
 
-	channel, ok := s.channels[id]
+func (s *InMemoryStorage) getByChannelID(channelID string, use bool) (*slack.Channel, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.getByChannelIDLocked(channelID, use)
+}
+
+// getByChannelIDLocked returns a copy of the channel, recording it as used if use is true. it requires the read lock.
+func (s *InMemoryStorage) getByChannelIDLocked(channelID string, use bool) (*slack.Channel, error) {
+	channel, ok := s.channels[channelID]
 	if !ok {
 		return nil, ErrNotFound
 	}
-
+	if use {
+		s.used(channelID)
+	}
+	channel = cloneChannel(channel)
 	return &channel, nil
 }
 
@@ -75,5 +260,185 @@ func (s *InMemoryStorage) NeedRefresh(ctx context.Context) bool {
 	if s.expredDuration == 0 {
 		return false
 	}
-	return time.Since(s.lastSetTime) > s.expredDuration
+	return since(s.lastSetTime) > s.expredDuration
+}
+
+func (s *InMemoryStorage) SearchContains(ctx context.Context, substr string, limit int) ([]slack.Channel, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	substr = strings.ToLower(substr)
+	channels := make([]slack.Channel, 0)
+	for _, channel := range s.channels {
+		if !strings.Contains(strings.ToLower(channel.Name), substr) {
+			continue
+		}
+		channels = append(channels, cloneChannel(channel))
+	}
+	sort.Slice(channels, func(i, j int) bool {
+		return channels[i].Name < channels[j].Name
+	})
+	if limit > 0 && len(channels) > limit {
+		channels = channels[:limit]
+	}
+	return channels, nil
+}
+
+func (s *InMemoryStorage) all() []slack.Channel {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	channels := make([]slack.Channel, 0, len(s.channels))
+	for _, channel := range s.channels {
+		channels = append(channels, cloneChannel(channel))
+	}
+	sort.Slice(channels, func(i, j int) bool {
+		return channels[i].ID < channels[j].ID
+	})
+	return channels
+}
+
+func (s *InMemoryStorage) GetSyncState(ctx context.Context) (SyncState, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.syncState, nil
+}
+
+func (s *InMemoryStorage) SetSyncState(ctx context.Context, state SyncState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.syncState = state
+	return nil
+}
+
+func (s *InMemoryStorage) SetChannelPriority(prefer func(a, b *slack.Channel) bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prefer = prefer
+}
+
+// keepCurrent reports whether the channel currently indexed by the key should be kept over the given channel.
+func (s *InMemoryStorage) keepCurrent(key string, channel *slack.Channel) bool {
+	id, ok := s.namesById[key]
+	if !ok || id == channel.ID {
+		return false
+	}
+	current, ok := s.channels[id]
+	if !ok {
+		return false
+	}
+	// the name of a channel always wins over an additional key of another channel.
+	if (key == channel.Name) != (key == current.Name) {
+		return key == current.Name
+	}
+	if s.prefer == nil {
+		return false
+	}
+	return !s.prefer(channel, &current)
+}
+
+// SetKeyFunc sets the function returning additional lookup keys for a channel. the channel name is always a key.
+// it applies to channels set after the call.
+func (s *InMemoryStorage) SetKeyFunc(keyFunc func(channel *slack.Channel) []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keyFunc = keyFunc
+}
+
+func (s *InMemoryStorage) keys(channel *slack.Channel) []string {
+	keys := []string{channel.Name}
+	if s.keyFunc == nil {
+		return keys
+	}
+	for _, key := range s.keyFunc(channel) {
+		if key == "" || key == channel.Name {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// unindex removes the keys pointing to the channel, except the given keys to keep.
+func (s *InMemoryStorage) unindex(id string, keep []string) {
+	for _, key := range s.keysById[id] {
+		if containsString(keep, key) {
+			continue
+		}
+		if s.namesById[key] == id {
+			delete(s.namesById, key)
+		}
+	}
+}
+
+// DeleteChannels removes the channels and all their keys from the storage.
+func (s *InMemoryStorage) DeleteChannels(ctx context.Context, ids ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deleteChannels(ids)
+	return nil
+}
+
+func (s *InMemoryStorage) deleteChannels(ids []string) {
+	for _, id := range ids {
+		s.unindex(id, nil)
+		channel, ok := s.channels[id]
+		delete(s.keysById, id)
+		delete(s.channels, id)
+		delete(s.ages, id)
+		if ok {
+			s.removeNameID(channel.Name, id)
+			s.promote(channel.Name)
+		}
+	}
+}
+
+func containsString(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+// CacheSize returns the number of cached channels, and a rough estimate of the memory footprint,
+// from the struct size and the lengths of the names and IDs. referenced data such as members are not counted.
+func (s *InMemoryStorage) CacheSize(ctx context.Context) (CacheSize, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	approx := int64(len(s.channels)) * int64(unsafe.Sizeof(slack.Channel{}))
+	for name, id := range s.namesById {
+		approx += int64(len(name) + len(id))
+	}
+	return CacheSize{
+		Channels:    int64(len(s.channels)),
+		ApproxBytes: approx,
+	}, nil
+}
+
+// cloneChannel returns a copy of the channel that shares no slices or pointers with the original.
+func cloneChannel(channel slack.Channel) slack.Channel {
+	channel.Members = cloneStrings(channel.Members)
+	channel.ConnectedTeamIDs = cloneStrings(channel.ConnectedTeamIDs)
+	channel.SharedTeamIDs = cloneStrings(channel.SharedTeamIDs)
+	channel.InternalTeamIDs = cloneStrings(channel.InternalTeamIDs)
+	if channel.Latest != nil {
+		latest := *channel.Latest
+		channel.Latest = &latest
+	}
+	return channel
+}
+
+func cloneStrings(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	return append(make([]string, 0, len(values)), values...)
 }
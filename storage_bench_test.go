@@ -0,0 +1,47 @@
+package slackcnr_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+)
+
+func benchmarkChannels(n int) []slack.Channel {
+	channels := make([]slack.Channel, n)
+	for i := range channels {
+		channels[i] = newTestChannel(fmt.Sprintf("C%08d", i), fmt.Sprintf("channel-%d", i))
+	}
+	return channels
+}
+
+func BenchmarkSetChannels(b *testing.B) {
+	channels := benchmarkChannels(50000)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		storage := slackcnr.NewInMemoryStorage(0)
+		if err := storage.SetChannels(ctx, channels); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGetByChannelName(b *testing.B) {
+	channels := benchmarkChannels(50000)
+	ctx := context.Background()
+	storage := slackcnr.NewInMemoryStorage(0)
+	if err := storage.SetChannels(ctx, channels); err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := storage.GetByChannelName(ctx, channels[i%len(channels)].Name); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
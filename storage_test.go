@@ -0,0 +1,178 @@
+package slackcnr_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestChannel(id, name string) slack.Channel {
+	return slack.Channel{
+		GroupConversation: slack.GroupConversation{
+			Conversation: slack.Conversation{
+				ID: id,
+			},
+			Name: name,
+		},
+	}
+}
+
+func TestInMemoryStorageSearchContains(t *testing.T) {
+	storage := slackcnr.NewInMemoryStorage(0)
+	ctx := context.Background()
+	require.NoError(t, storage.SetChannels(ctx, []slack.Channel{
+		newTestChannel("C1", "incident-b"),
+		newTestChannel("C2", "general"),
+		newTestChannel("C3", "ops-Incident"),
+		newTestChannel("C4", "incident-a"),
+	}))
+
+	channels, err := storage.SearchContains(ctx, "INCIDENT", 0)
+	require.NoError(t, err)
+	names := make([]string, 0, len(channels))
+	for _, c := range channels {
+		names = append(names, c.Name)
+	}
+	require.Equal(t, []string{"incident-a", "incident-b", "ops-Incident"}, names)
+
+	channels, err = storage.SearchContains(ctx, "incident", 2)
+	require.NoError(t, err)
+	require.Len(t, channels, 2)
+	require.Equal(t, "incident-a", channels[0].Name)
+}
+
+func TestInMemoryStorage__Rename(t *testing.T) {
+	storage := slackcnr.NewInMemoryStorage(0)
+	ctx := context.Background()
+	require.NoError(t, storage.SetChannels(ctx, []slack.Channel{newTestChannel("C1", "old-name")}))
+	require.NoError(t, storage.SetChannels(ctx, []slack.Channel{newTestChannel("C1", "new-name")}))
+	_, err := storage.GetByChannelName(ctx, "old-name")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound)
+	channel, err := storage.GetByChannelName(ctx, "new-name")
+	require.NoError(t, err)
+	require.Equal(t, "C1", channel.ID)
+}
+
+func TestInMemoryStorage__KeyFunc(t *testing.T) {
+	storage := slackcnr.NewInMemoryStorage(0)
+	storage.SetKeyFunc(func(channel *slack.Channel) []string {
+		var custom struct {
+			Service string `json:"service"`
+			Alias   string `json:"alias"`
+		}
+		if err := json.Unmarshal([]byte(channel.Topic.Value), &custom); err != nil {
+			return nil
+		}
+		return []string{custom.Service, custom.Alias}
+	})
+	ctx := context.Background()
+	channel := newTestChannel("C1", "team-payments")
+	channel.Topic = slack.Topic{Value: `{"service":"payments-api","alias":"pay"}`}
+	require.NoError(t, storage.SetChannels(ctx, []slack.Channel{channel}))
+
+	for _, key := range []string{"team-payments", "payments-api", "pay"} {
+		got, err := storage.GetByChannelName(ctx, key)
+		require.NoError(t, err, key)
+		require.Equal(t, "C1", got.ID)
+	}
+	size, err := storage.CacheSize(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, size.Channels)
+
+	require.NoError(t, storage.DeleteChannels(ctx, "C1"))
+	for _, key := range []string{"team-payments", "payments-api", "pay"} {
+		_, err := storage.GetByChannelName(ctx, key)
+		require.ErrorIs(t, err, slackcnr.ErrNotFound, key)
+	}
+}
+
+type failingStorage struct {
+	*slackcnr.InMemoryStorage
+	calls  int
+	failAt int
+}
+
+func (s *failingStorage) SetChannels(ctx context.Context, channels []slack.Channel) error {
+	s.calls++
+	if s.calls >= s.failAt {
+		return errors.New("storage error")
+	}
+	return s.InMemoryStorage.SetChannels(ctx, channels)
+}
+
+func TestResolverRefresh__StorageFailureKeepsCacheConsistent(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	storage := &failingStorage{InMemoryStorage: slackcnr.NewInMemoryStorage(0), failAt: 2}
+	r := slackcnr.New(client, slackcnr.WithCacheStorage(storage))
+	ctx := context.Background()
+
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C1", "first"),
+	}, "", nil).Once()
+	require.NoError(t, r.Refresh(ctx))
+
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C1", "first-renamed"),
+	}, "next", nil).Once()
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C2", "second"),
+	}, "", nil).Once()
+	require.Error(t, r.Refresh(ctx))
+	require.Equal(t, 2, storage.calls, "channels of all pages are written at once")
+	channel, err := storage.GetByChannelName(ctx, "first")
+	require.NoError(t, err, "the cache is not partially updated")
+	require.Equal(t, "C1", channel.ID)
+	_, err = storage.GetByChannelName(ctx, "first-renamed")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound)
+}
+
+func TestResolverRefresh__StorageFailureNeedsRefresh(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	storage := &failingStorage{InMemoryStorage: slackcnr.NewInMemoryStorage(0), failAt: 1}
+	r := slackcnr.New(client, slackcnr.WithCacheStorage(storage))
+	ctx := context.Background()
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C1", "first"),
+	}, "next", nil).Once()
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C2", "second"),
+	}, "", nil).Once()
+	require.Error(t, r.Refresh(ctx))
+	require.True(t, storage.NeedRefresh(ctx))
+}
+
+func TestInMemoryStorage__ReturnsCopies(t *testing.T) {
+	storage := slackcnr.NewInMemoryStorage(0)
+	ctx := context.Background()
+	channel := newTestChannel("C1", "test")
+	channel.Members = []string{"U1", "U2"}
+	channel.Latest = &slack.Message{Msg: slack.Msg{Text: "hello"}}
+	require.NoError(t, storage.SetChannels(ctx, []slack.Channel{channel}))
+	channel.Members[0] = "mutated"
+
+	got, err := storage.GetByChannelName(ctx, "test")
+	require.NoError(t, err)
+	require.Equal(t, []string{"U1", "U2"}, got.Members, "mutating the input must not affect the cache")
+	got.Name = "mutated"
+	got.Members[1] = "mutated"
+	got.Latest.Text = "mutated"
+
+	searched, err := storage.SearchContains(ctx, "test", 0)
+	require.NoError(t, err)
+	require.Len(t, searched, 1)
+	searched[0].Members[0] = "mutated"
+
+	got, err = storage.GetByChannelName(ctx, "test")
+	require.NoError(t, err)
+	require.Equal(t, "test", got.Name)
+	require.Equal(t, []string{"U1", "U2"}, got.Members)
+	require.Equal(t, "hello", got.Latest.Text)
+}
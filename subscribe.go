@@ -0,0 +1,56 @@
+package slackcnr
+
+import (
+	"sync"
+	"time"
+)
+
+// RefreshEvent is delivered to subscribers when a refresh completes.
+type RefreshEvent struct {
+	// Channels is the number of channels fetched by the refresh.
+	Channels int64
+	// Err is the error of the refresh, nil on success.
+	Err error
+	// Time is the time the refresh completed.
+	Time time.Time
+}
+
+type subscribers struct {
+	mu   sync.Mutex
+	next int
+	chs  map[int]chan RefreshEvent
+}
+
+// Subscribe returns a channel that receives an event each time a refresh completes, and a function to unsubscribe.
+// events are sent without blocking, so an event is dropped if the subscriber has not received the previous one.
+func (r *Resolver) Subscribe() (<-chan RefreshEvent, func()) {
+	r.subscribers.mu.Lock()
+	defer r.subscribers.mu.Unlock()
+	if r.subscribers.chs == nil {
+		r.subscribers.chs = make(map[int]chan RefreshEvent)
+	}
+	id := r.subscribers.next
+	r.subscribers.next++
+	ch := make(chan RefreshEvent, 1)
+	r.subscribers.chs[id] = ch
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			r.subscribers.mu.Lock()
+			defer r.subscribers.mu.Unlock()
+			delete(r.subscribers.chs, id)
+			close(ch)
+		})
+	}
+}
+
+func (s *subscribers) publish(event RefreshEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, ch := range s.chs {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package slackcnr_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolverSubscribe(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C012345678", "test"),
+		newTestChannel("C023456789", "test2"),
+	}, "", nil)
+	r := slackcnr.New(client, slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)))
+	events, unsubscribe := r.Subscribe()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	require.NoError(t, r.Refresh(ctx))
+	select {
+	case event := <-events:
+		require.NoError(t, event.Err)
+		require.EqualValues(t, 2, event.Channels)
+	case <-ctx.Done():
+		t.Fatal("refresh event not received")
+	}
+
+	unsubscribe()
+	require.NoError(t, r.Refresh(ctx))
+	_, ok := <-events
+	require.False(t, ok, "channel should be closed after unsubscribe")
+	unsubscribe()
+}
@@ -0,0 +1,46 @@
+package slackcnr
+
+import (
+	"context"
+	"errors"
+)
+
+type storageRef struct {
+	Storage
+}
+
+// cacheStorage returns the current cache storage.
+func (r *Resolver) cacheStorage() Storage {
+	return r.storage.Load().Storage
+}
+
+// SetStorage replaces the cache storage at runtime, such as during a migration to another backend.
+// if migrate is true, the cached channels are copied into the new storage, which requires the current storage to implement ListableStorage.
+// the storage-side options such as WithKeyFunc are applied to the new storage.
+// the swap waits for an in-flight refresh. after the swap, a lookup triggers a refresh if the new storage reports NeedRefresh.
+func (r *Resolver) SetStorage(ctx context.Context, storage Storage, migrate bool) error {
+	ctx = ensureContext(ctx)
+	if storage == nil {
+		return errors.New("cache storage is nil")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.configureStorage(storage)
+	if migrate {
+		listable, ok := r.cacheStorage().(ListableStorage)
+		if !ok {
+			return ErrListNotSupported
+		}
+		channels, err := listable.List(ctx)
+		if err != nil {
+			return err
+		}
+		if err := storage.SetChannels(ctx, channels); err != nil {
+			return err
+		}
+	}
+	r.storage.Store(&storageRef{Storage: storage})
+	// the channels written to the previous storage are unknown to the new one.
+	r.changes.reset()
+	return nil
+}
@@ -0,0 +1,59 @@
+package slackcnr_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolverSetStorage__Migrate(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C012345678", "general"),
+	}, "", nil).Once()
+	r := slackcnr.New(client, slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)))
+	ctx := context.Background()
+	require.NoError(t, r.Refresh(ctx))
+
+	next := slackcnr.NewInMemoryStorage(0)
+	require.NoError(t, r.SetStorage(ctx, next, true))
+	channel, err := next.GetByChannelName(ctx, "general")
+	require.NoError(t, err)
+	require.Equal(t, "C012345678", channel.ID)
+	channel, err = r.Lookup(ctx, "general")
+	require.NoError(t, err)
+	require.Equal(t, "C012345678", channel.ID)
+	client.AssertNumberOfCalls(t, "GetConversationsForUserContext", 1)
+}
+
+func TestResolverSetStorage__WithoutMigrate(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C012345678", "general"),
+	}, "", nil).Twice()
+	r := slackcnr.New(client, slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)))
+	ctx := context.Background()
+	require.NoError(t, r.Refresh(ctx))
+
+	next := slackcnr.NewInMemoryStorage(0)
+	require.NoError(t, r.SetStorage(ctx, next, false))
+	require.True(t, next.NeedRefresh(ctx))
+	// the fresh storage reports NeedRefresh, so the lookup triggers a refresh.
+	channel, err := r.Lookup(ctx, "general")
+	require.NoError(t, err)
+	require.Equal(t, "C012345678", channel.ID)
+	client.AssertNumberOfCalls(t, "GetConversationsForUserContext", 2)
+}
+
+func TestResolverSetStorage__MigrateNotSupported(t *testing.T) {
+	r := slackcnr.New(&mockSlackClient{t: t}, slackcnr.WithCacheStorage(slackcnr.NewLRUStorage(10, 0)))
+	err := r.SetStorage(context.Background(), slackcnr.NewInMemoryStorage(0), true)
+	require.ErrorIs(t, err, slackcnr.ErrListNotSupported)
+	require.Error(t, r.SetStorage(context.Background(), nil, false))
+}
@@ -0,0 +1,82 @@
+package slackcnr
+
+import (
+	"context"
+	"errors"
+
+	"github.com/slack-go/slack"
+)
+
+// SyncState is the state of incremental sync, persisted by SyncStateStorage.
+type SyncState struct {
+	// Cursors is the cursor of the last fetched page, by refresh source API method name.
+	Cursors map[string]string `json:"cursors,omitempty"`
+}
+
+// SyncStateStorage is an optional interface for storages that persist the incremental sync state.
+type SyncStateStorage interface {
+	Storage
+	GetSyncState(ctx context.Context) (SyncState, error)
+	SetSyncState(ctx context.Context, state SyncState) error
+}
+
+// WithIncrementalSync resumes each refresh from the cursor of the last page fetched by the previous refresh,
+// instead of paginating from the beginning.
+// the cursor is persisted when the cache storage implements SyncStateStorage, otherwise this option has no effect.
+//
+// Slack does not support fetching conversations "since" a point, so this is an approximation:
+// channels are returned in a stable order, and new channels are expected to appear on the last pages.
+// channels inserted into earlier pages, and changes to channels on earlier pages such as renames, are not detected until a full refresh.
+// when the persisted cursor is rejected as invalid, the refresh falls back to full pagination.
+// combine with an expiring storage, or call Refresh on a fresh storage periodically, to pick up such changes.
+func WithIncrementalSync() ResolverOption {
+	return func(o *resolverOptions) {
+		o.incrementalSync = true
+	}
+}
+
+func (r *Resolver) syncStateStorage() (SyncStateStorage, bool) {
+	if !r.opts.incrementalSync {
+		return nil, false
+	}
+	s, ok := r.cacheStorage().(SyncStateStorage)
+	return s, ok
+}
+
+func (r *Resolver) loadSyncCursor(ctx context.Context, source refreshSource) string {
+	s, ok := r.syncStateStorage()
+	if !ok {
+		return ""
+	}
+	state, err := s.GetSyncState(ctx)
+	if err != nil {
+		return ""
+	}
+	return state.Cursors[string(source)]
+}
+
+func (r *Resolver) saveSyncCursor(ctx context.Context, source refreshSource, cursor string) error {
+	s, ok := r.syncStateStorage()
+	if !ok {
+		return nil
+	}
+	state, err := s.GetSyncState(ctx)
+	if err != nil {
+		return err
+	}
+	cursors := make(map[string]string, len(state.Cursors)+1)
+	for k, v := range state.Cursors {
+		cursors[k] = v
+	}
+	cursors[string(source)] = cursor
+	state.Cursors = cursors
+	return s.SetSyncState(ctx, state)
+}
+
+func isInvalidCursor(err error) bool {
+	var slackErr slack.SlackErrorResponse
+	if !errors.As(err, &slackErr) {
+		return false
+	}
+	return slackErr.Err == "invalid_cursor"
+}
@@ -0,0 +1,62 @@
+package slackcnr_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func userConversationsParams(cursor string) *slack.GetConversationsForUserParameters {
+	return &slack.GetConversationsForUserParameters{
+		Cursor: cursor,
+		Limit:  1000,
+	}
+}
+
+func TestResolverRefresh__IncrementalSync(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	storage := slackcnr.NewInMemoryStorage(0)
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(storage),
+		slackcnr.WithIncrementalSync(),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client.On("GetConversationsForUserContext", mock.Anything, userConversationsParams("")).Return([]slack.Channel{
+		newTestChannel("C1", "first"),
+	}, "cursor1", nil).Once()
+	client.On("GetConversationsForUserContext", mock.Anything, userConversationsParams("cursor1")).Return([]slack.Channel{
+		newTestChannel("C2", "second"),
+	}, "", nil).Once()
+	require.NoError(t, r.Refresh(ctx))
+	state, err := storage.GetSyncState(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "cursor1", state.Cursors["users.conversations"])
+
+	// resumes from the last page, not from the beginning.
+	client.On("GetConversationsForUserContext", mock.Anything, userConversationsParams("cursor1")).Return([]slack.Channel{
+		newTestChannel("C2", "second"),
+		newTestChannel("C3", "third"),
+	}, "", nil).Once()
+	require.NoError(t, r.Refresh(ctx))
+	channel, err := r.Lookup(ctx, "third")
+	require.NoError(t, err)
+	require.Equal(t, "C3", channel.ID)
+
+	// falls back to full pagination when the cursor is rejected.
+	client.On("GetConversationsForUserContext", mock.Anything, userConversationsParams("cursor1")).Return([]slack.Channel{}, "", slack.SlackErrorResponse{Err: "invalid_cursor"}).Once()
+	client.On("GetConversationsForUserContext", mock.Anything, userConversationsParams("")).Return([]slack.Channel{
+		newTestChannel("C1", "first"),
+	}, "", nil).Once()
+	require.NoError(t, r.Refresh(ctx))
+	state, err = storage.GetSyncState(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "", state.Cursors["users.conversations"])
+}
@@ -0,0 +1,23 @@
+package slackcnr
+
+import (
+	"context"
+	"time"
+)
+
+// WithPerCallTimeout bounds each Slack API call, such as a single page of a refresh, by the timeout.
+// a stalled call fails fast with context.DeadlineExceeded instead of stalling the whole refresh.
+// the timeout never extends the deadline of the parent context.
+func WithPerCallTimeout(d time.Duration) ResolverOption {
+	return func(o *resolverOptions) {
+		o.perCallTimeout = d
+	}
+}
+
+// callContext returns the context for a single API call.
+func (r *Resolver) callContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.opts.perCallTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, r.opts.perCallTimeout)
+}
@@ -0,0 +1,52 @@
+package slackcnr_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolverRefresh__PerCallTimeout(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		ctx := args.Get(0).(context.Context)
+		select {
+		case <-ctx.Done():
+		case <-time.After(5 * time.Second):
+		}
+	}).Return([]slack.Channel{}, "", context.DeadlineExceeded).Once()
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+		slackcnr.WithPerCallTimeout(20*time.Millisecond),
+	)
+	ctx := context.Background()
+	started := time.Now()
+	err := r.Refresh(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(started), time.Second)
+	require.NoError(t, ctx.Err(), "the parent context is not canceled")
+}
+
+func TestResolverRefresh__PerCallTimeoutDoesNotExtendParentDeadline(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	parentDeadline, _ := ctx.Deadline()
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		deadline, ok := args.Get(0).(context.Context).Deadline()
+		require.True(t, ok)
+		require.False(t, deadline.After(parentDeadline))
+	}).Return([]slack.Channel{}, "", nil).Once()
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+		slackcnr.WithPerCallTimeout(time.Hour),
+	)
+	require.NoError(t, r.Refresh(ctx))
+}
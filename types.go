@@ -0,0 +1,80 @@
+package slackcnr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TokenKind is the kind of the token the slack client is authenticated with.
+type TokenKind int
+
+const (
+	// TokenKindUnknown keeps the API default conversation types.
+	TokenKindUnknown TokenKind = iota
+	// TokenKindBot is a bot token (xoxb-).
+	TokenKindBot
+	// TokenKindUser is a user token (xoxp-).
+	TokenKindUser
+)
+
+// String returns the name of the token kind.
+func (k TokenKind) String() string {
+	switch k {
+	case TokenKindBot:
+		return "bot"
+	case TokenKindUser:
+		return "user"
+	default:
+		return "unknown"
+	}
+}
+
+// WithTokenKind tells the resolver the kind of the token, used to choose the default conversation types.
+// with a bot token, public and private channels the bot is a member of are fetched.
+// with a user token, the user's group DMs are also fetched.
+// without it, the API default (public channels only) is kept.
+func WithTokenKind(kind TokenKind) ResolverOption {
+	return func(o *resolverOptions) {
+		o.tokenKind = kind
+	}
+}
+
+// knownChannelTypes are the conversation types accepted by the Slack API.
+var knownChannelTypes = []string{"public_channel", "private_channel", "mpim", "im"}
+
+// WithChannelTypes sets the conversation types fetched with users.conversations API,
+// e.g. "public_channel", "private_channel", "mpim", "im". it takes precedence over WithTokenKind.
+// the types are lowercased. NewChecked returns an error for an unknown type, and New logs a warning,
+// since Slack silently returns no channel for it.
+func WithChannelTypes(types ...string) ResolverOption {
+	return func(o *resolverOptions) {
+		o.channelTypes = make([]string, 0, len(types))
+		for _, t := range types {
+			o.channelTypes = append(o.channelTypes, strings.ToLower(strings.TrimSpace(t)))
+		}
+	}
+}
+
+func (o *resolverOptions) validateChannelTypes() error {
+	for _, t := range o.channelTypes {
+		if !containsString(knownChannelTypes, t) {
+			return fmt.Errorf("unknown channel type %q, must be one of %s", t, strings.Join(knownChannelTypes, ", "))
+		}
+	}
+	return nil
+}
+
+// conversationTypes returns the types passed to users.conversations API. nil means the API default.
+func (o *resolverOptions) conversationTypes() []string {
+	if len(o.channelTypes) > 0 {
+		return cloneStrings(o.channelTypes)
+	}
+	switch o.tokenKind {
+	case TokenKindBot:
+		return []string{"public_channel", "private_channel"}
+	case TokenKindUser:
+		return []string{"public_channel", "private_channel", "mpim"}
+	default:
+		return nil
+	}
+}
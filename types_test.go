@@ -0,0 +1,74 @@
+package slackcnr_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolver__DefaultChannelTypes(t *testing.T) {
+	cases := []struct {
+		name     string
+		opts     []slackcnr.ResolverOption
+		expected []string
+	}{
+		{
+			name:     "unknown",
+			expected: nil,
+		},
+		{
+			name:     "bot",
+			opts:     []slackcnr.ResolverOption{slackcnr.WithTokenKind(slackcnr.TokenKindBot)},
+			expected: []string{"public_channel", "private_channel"},
+		},
+		{
+			name:     "user",
+			opts:     []slackcnr.ResolverOption{slackcnr.WithTokenKind(slackcnr.TokenKindUser)},
+			expected: []string{"public_channel", "private_channel", "mpim"},
+		},
+		{
+			name: "explicit",
+			opts: []slackcnr.ResolverOption{
+				slackcnr.WithTokenKind(slackcnr.TokenKindUser),
+				slackcnr.WithChannelTypes("private_channel"),
+			},
+			expected: []string{"private_channel"},
+		},
+		{
+			name:     "lowercased",
+			opts:     []slackcnr.ResolverOption{slackcnr.WithChannelTypes(" Public_Channel", "MPIM")},
+			expected: []string{"public_channel", "mpim"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client := &mockSlackClient{t: t}
+			defer client.AssertExpectations(t)
+			client.On("GetConversationsForUserContext", mock.Anything, &slack.GetConversationsForUserParameters{
+				Types: c.expected,
+				Limit: 1000,
+			}).Return([]slack.Channel{newTestChannel("C1", "test")}, "", nil).Once()
+			resolver := slackcnr.New(client, append(c.opts, slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)))...)
+			require.NoError(t, resolver.Refresh(context.Background()))
+			require.Equal(t, c.expected, resolver.Config().ChannelTypes)
+		})
+	}
+}
+
+func TestNewChecked__InvalidChannelTypes(t *testing.T) {
+	_, err := slackcnr.NewChecked(&mockSlackClient{t: t}, slackcnr.WithChannelTypes("public_channel", "public_channels"))
+	require.ErrorContains(t, err, `unknown channel type "public_channels"`)
+
+	var buf bytes.Buffer
+	slackcnr.New(&mockSlackClient{t: t},
+		slackcnr.WithChannelTypes("public_channels"),
+		slackcnr.WithLogger(slog.New(slog.NewTextHandler(&buf, nil))),
+	)
+	require.Contains(t, buf.String(), "invalid channel types")
+}
@@ -0,0 +1,119 @@
+package slackcnr
+
+import (
+	"context"
+	"errors"
+
+	"github.com/slack-go/slack"
+)
+
+// ErrUpdateNotSupported is returned by UpdateChannel and Invalidate when the cache storage does not support partial updates.
+var ErrUpdateNotSupported = errors.New("cache storage does not support partial updates")
+
+// UpdateChannel stores or replaces a single channel in the cache storage, without a full refresh.
+// like a backfill, it does not extend the cache expiry. a renamed channel is tracked by WithTrackRenames.
+// a channel excluded by WithExcludeArchived or WithArchivedOnly is invalidated instead.
+// it requires the cache storage to implement BackfillStorage, otherwise it returns ErrUpdateNotSupported.
+func (r *Resolver) UpdateChannel(ctx context.Context, channel slack.Channel) error {
+	ctx = ensureContext(ctx)
+	s, ok := r.cacheStorage().(BackfillStorage)
+	if !ok {
+		return ErrUpdateNotSupported
+	}
+	if r.opts.excludeArchived && channel.IsArchived || r.opts.archivedOnly && !channel.IsArchived {
+		return r.Invalidate(ctx, channel.ID)
+	}
+	channels := r.storedChannels([]slack.Channel{channel})
+	channels = r.opts.filterChannels(channels)
+	if len(channels) == 0 {
+		return nil
+	}
+	if err := s.AddChannels(ctx, channels); err != nil {
+		return err
+	}
+	if r.opts.trackRenames {
+		r.renames.observe(r.opts.normalizeChannels(channels), r.opts.renameRetention)
+	}
+	return nil
+}
+
+// Invalidate removes the channels from the cache storage, without a full refresh.
+// it requires the cache storage to implement DeletableStorage, otherwise it returns ErrUpdateNotSupported.
+func (r *Resolver) Invalidate(ctx context.Context, channelIDs ...string) error {
+	ctx = ensureContext(ctx)
+	s, ok := r.cacheStorage().(DeletableStorage)
+	if !ok {
+		return ErrUpdateNotSupported
+	}
+	if err := s.DeleteChannels(ctx, channelIDs...); err != nil {
+		return err
+	}
+	r.changes.forget(channelIDs)
+	return nil
+}
+
+// PredicateDeletableStorage is an optional interface for storages that can remove the channels matching a predicate at once.
+type PredicateDeletableStorage interface {
+	Storage
+	// DeleteWhere removes the channels for which pred returns true, and returns the number of removed channels.
+	DeleteWhere(ctx context.Context, pred func(slack.Channel) bool) (int, error)
+}
+
+var _ PredicateDeletableStorage = (*InMemoryStorage)(nil)
+
+// InvalidateWhere removes the cached channels for which pred returns true, such as all archived channels,
+// and returns the number of removed channels.
+// with a PredicateDeletableStorage, the channels are matched and removed atomically.
+// otherwise it falls back to ListableStorage and DeletableStorage, and returns ErrUpdateNotSupported without them.
+func (r *Resolver) InvalidateWhere(ctx context.Context, pred func(slack.Channel) bool) (int, error) {
+	ctx = ensureContext(ctx)
+	storage := r.cacheStorage()
+	if s, ok := storage.(PredicateDeletableStorage); ok {
+		n, err := s.DeleteWhere(ctx, pred)
+		if n > 0 {
+			// the removed channels are not known, so the next refresh writes all channels.
+			r.changes.reset()
+		}
+		return n, err
+	}
+	listable, ok := storage.(ListableStorage)
+	if !ok {
+		return 0, ErrUpdateNotSupported
+	}
+	if _, ok := storage.(DeletableStorage); !ok {
+		return 0, ErrUpdateNotSupported
+	}
+	channels, err := listable.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var ids []string
+	for _, channel := range channels {
+		if pred(channel) {
+			ids = append(ids, channel.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	if err := r.Invalidate(ctx, ids...); err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
+
+// DeleteWhere removes the channels for which pred returns true under the lock.
+// pred is called with a copy of each channel, and must not call the storage.
+func (s *InMemoryStorage) DeleteWhere(ctx context.Context, pred func(slack.Channel) bool) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ids []string
+	for id, channel := range s.channels {
+		if pred(cloneChannel(channel)) {
+			ids = append(ids, id)
+		}
+	}
+	s.deleteChannels(ids)
+	return len(ids), nil
+}
@@ -0,0 +1,41 @@
+package slackcnr_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolverUpdateChannel__NotSupported(t *testing.T) {
+	ctx := context.Background()
+	r := slackcnr.New(&mockSlackClient{t: t}, slackcnr.WithCacheStorage(slackcnr.NewLRUStorage(10, 0)))
+	require.ErrorIs(t, r.UpdateChannel(ctx, newTestChannel("C012345678", "general")), slackcnr.ErrUpdateNotSupported)
+	require.ErrorIs(t, r.Invalidate(ctx, "C012345678"), slackcnr.ErrUpdateNotSupported)
+}
+
+func TestResolverInvalidateWhere(t *testing.T) {
+	ctx := context.Background()
+	storage := slackcnr.NewInMemoryStorage(0)
+	require.NoError(t, storage.SetChannels(ctx, []slack.Channel{
+		newTestChannel("C1", "team-a-general"),
+		newTestChannel("C2", "team-a-random"),
+		newTestChannel("C3", "team-b-general"),
+	}))
+	r := slackcnr.New(&mockSlackClient{t: t}, slackcnr.WithCacheStorage(storage))
+	removed, err := r.InvalidateWhere(ctx, func(channel slack.Channel) bool {
+		return strings.HasPrefix(channel.Name, "team-a-")
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, removed)
+	names, err := r.ListNames(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"team-b-general"}, names)
+
+	_, err = slackcnr.New(&mockSlackClient{t: t}, slackcnr.WithCacheStorage(slackcnr.NewLRUStorage(10, 0))).
+		InvalidateWhere(ctx, func(slack.Channel) bool { return true })
+	require.ErrorIs(t, err, slackcnr.ErrUpdateNotSupported)
+}
@@ -0,0 +1,50 @@
+package slackcnr
+
+import (
+	"context"
+	"strings"
+)
+
+// ChannelView is a minimal, stable shape of a channel for templates, decoupled from slack.Channel.
+type ChannelView struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	IsPrivate  bool   `json:"is_private"`
+	IsArchived bool   `json:"is_archived"`
+	// Permalink is the URL of the channel, empty unless WithTeamDomain is set.
+	Permalink string `json:"permalink,omitempty"`
+}
+
+// WithTeamDomain sets the workspace domain used to build ChannelView.Permalink,
+// either the subdomain like "myteam" or the host like "myorg.enterprise.slack.com".
+func WithTeamDomain(domain string) ResolverOption {
+	return func(o *resolverOptions) {
+		o.teamDomain = domain
+	}
+}
+
+// LookupView finds a channel by name like Lookup, and returns its view, such as for {{ channel "general" }} in templates.
+func (r *Resolver) LookupView(ctx context.Context, channelName string) (ChannelView, error) {
+	channel, err := r.Lookup(ctx, channelName)
+	if err != nil {
+		return ChannelView{}, err
+	}
+	return ChannelView{
+		ID:         channel.ID,
+		Name:       channel.Name,
+		IsPrivate:  channel.IsPrivate,
+		IsArchived: channel.IsArchived,
+		Permalink:  r.opts.channelPermalink(channel.ID),
+	}, nil
+}
+
+func (o *resolverOptions) channelPermalink(channelID string) string {
+	host := strings.TrimSuffix(strings.TrimPrefix(o.teamDomain, "https://"), "/")
+	if host == "" {
+		return ""
+	}
+	if !strings.Contains(host, ".") {
+		host += ".slack.com"
+	}
+	return "https://" + host + "/archives/" + channelID
+}
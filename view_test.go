@@ -0,0 +1,51 @@
+package slackcnr_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolverLookupView(t *testing.T) {
+	ctx := context.Background()
+	storage := slackcnr.NewInMemoryStorage(0)
+	channel := newTestChannel("C012345678", "secret")
+	channel.IsPrivate = true
+	channel.IsArchived = true
+	require.NoError(t, storage.SetChannels(ctx, []slack.Channel{channel}))
+
+	cases := []struct {
+		name      string
+		domain    string
+		permalink string
+	}{
+		{name: "no domain", permalink: ""},
+		{name: "subdomain", domain: "myteam", permalink: "https://myteam.slack.com/archives/C012345678"},
+		{name: "host", domain: "myorg.enterprise.slack.com", permalink: "https://myorg.enterprise.slack.com/archives/C012345678"},
+		{name: "url", domain: "https://myteam.slack.com/", permalink: "https://myteam.slack.com/archives/C012345678"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := slackcnr.New(&mockSlackClient{t: t},
+				slackcnr.WithCacheStorage(storage),
+				slackcnr.WithTeamDomain(c.domain),
+			)
+			view, err := r.LookupView(ctx, "secret")
+			require.NoError(t, err)
+			require.Equal(t, slackcnr.ChannelView{
+				ID:         "C012345678",
+				Name:       "secret",
+				IsPrivate:  true,
+				IsArchived: true,
+				Permalink:  c.permalink,
+			}, view)
+		})
+	}
+
+	r := slackcnr.New(&mockSlackClient{t: t}, slackcnr.WithCacheStorage(storage))
+	_, err := r.LookupView(ctx, "unknown")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound)
+}
@@ -0,0 +1,47 @@
+package slackcnr
+
+import "github.com/slack-go/slack"
+
+// Visibility is the classification of a channel derived from its flags.
+type Visibility int
+
+const (
+	VisibilityUnknown Visibility = iota
+	VisibilityPublic
+	VisibilityPrivate
+	VisibilityShared
+	VisibilityDM
+)
+
+func (v Visibility) String() string {
+	switch v {
+	case VisibilityPublic:
+		return "public"
+	case VisibilityPrivate:
+		return "private"
+	case VisibilityShared:
+		return "shared"
+	case VisibilityDM:
+		return "dm"
+	default:
+		return "unknown"
+	}
+}
+
+// ChannelVisibility classifies the channel.
+// direct messages (including multi-party) take precedence over shared, and shared takes precedence over private.
+func ChannelVisibility(c *slack.Channel) Visibility {
+	if c == nil {
+		return VisibilityUnknown
+	}
+	switch {
+	case c.IsIM || c.IsMpIM:
+		return VisibilityDM
+	case c.IsShared || c.IsExtShared || c.IsOrgShared:
+		return VisibilityShared
+	case c.IsPrivate || c.IsGroup:
+		return VisibilityPrivate
+	default:
+		return VisibilityPublic
+	}
+}
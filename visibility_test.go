@@ -0,0 +1,38 @@
+package slackcnr_test
+
+import (
+	"testing"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChannelVisibility(t *testing.T) {
+	cases := []struct {
+		name     string
+		conv     slack.Conversation
+		expected slackcnr.Visibility
+	}{
+		{name: "public", conv: slack.Conversation{}, expected: slackcnr.VisibilityPublic},
+		{name: "private", conv: slack.Conversation{IsPrivate: true}, expected: slackcnr.VisibilityPrivate},
+		{name: "group", conv: slack.Conversation{IsGroup: true}, expected: slackcnr.VisibilityPrivate},
+		{name: "shared", conv: slack.Conversation{IsShared: true}, expected: slackcnr.VisibilityShared},
+		{name: "ext_shared", conv: slack.Conversation{IsExtShared: true}, expected: slackcnr.VisibilityShared},
+		{name: "org_shared", conv: slack.Conversation{IsOrgShared: true}, expected: slackcnr.VisibilityShared},
+		{name: "private_shared", conv: slack.Conversation{IsPrivate: true, IsShared: true}, expected: slackcnr.VisibilityShared},
+		{name: "im", conv: slack.Conversation{IsIM: true}, expected: slackcnr.VisibilityDM},
+		{name: "mpim", conv: slack.Conversation{IsMpIM: true, IsPrivate: true}, expected: slackcnr.VisibilityDM},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			channel := &slack.Channel{
+				GroupConversation: slack.GroupConversation{
+					Conversation: c.conv,
+				},
+			}
+			require.Equal(t, c.expected, slackcnr.ChannelVisibility(channel))
+		})
+	}
+	require.Equal(t, slackcnr.VisibilityUnknown, slackcnr.ChannelVisibility(nil))
+}
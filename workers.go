@@ -0,0 +1,190 @@
+package slackcnr
+
+import (
+	"context"
+	"sync"
+
+	"github.com/slack-go/slack"
+)
+
+// WithRefreshWorkers sets how many refresh passes run concurrently in a single refresh, to bound parallelism under rate limits.
+// the passes are users.conversations and conversations.list, or with WithAdminEnumeration and more than one team ID,
+// admin.conversations.search for each team. default is 1, running the passes serially.
+// the channels are merged in the same order regardless of the concurrency.
+func WithRefreshWorkers(n int) ResolverOption {
+	return func(o *resolverOptions) {
+		o.refreshWorkers = n
+	}
+}
+
+// refreshTask is a single pass of a refresh.
+type refreshTask struct {
+	source refreshSource
+	fetch  func(ctx context.Context) ([]slack.Channel, string, error)
+	// tolerate reports whether the error skips the pass instead of failing the refresh.
+	tolerate func(err error) bool
+	// skip reports whether the pass is redundant given the results of the previous passes.
+	// it is checked only when the passes run serially.
+	skip func(done []refreshResult) bool
+}
+
+type refreshResult struct {
+	channels []slack.Channel
+	cursor   string
+	// err is a tolerated error of the pass.
+	err error
+}
+
+// refreshTasks returns the passes of a refresh in the order their channels are merged.
+func (r *Resolver) refreshTasks() []refreshTask {
+	if r.opts.adminEnumeration {
+		if r.opts.refreshWorkers <= 1 || len(r.opts.adminTeamIDs) <= 1 {
+			return []refreshTask{{
+				source: sourceAdminConversations,
+				fetch: func(ctx context.Context) ([]slack.Channel, string, error) {
+					return r.refreshAdminConversations(ctx, sourceAdminConversations, r.opts.adminTeamIDs)
+				},
+			}}
+		}
+		tasks := make([]refreshTask, 0, len(r.opts.adminTeamIDs))
+		for _, teamID := range r.opts.adminTeamIDs {
+			teamIDs := []string{teamID}
+			source := sourceAdminConversations + refreshSource(":"+teamID)
+			tasks = append(tasks, refreshTask{
+				source: source,
+				fetch: func(ctx context.Context) ([]slack.Channel, string, error) {
+					return r.refreshAdminConversations(ctx, source, teamIDs)
+				},
+			})
+		}
+		return tasks
+	}
+	var tasks []refreshTask
+	skipPublic := r.opts.skipPublicIfUserComplete && r.opts.useUserConversations() && r.opts.usePublicChannels() && !r.opts.incrementalSync
+	// userPages counts the pages of the users.conversations pass, and userCursor is the next cursor of its last page,
+	// for the skip check of the public channels pass, that runs after the pass when the passes run serially.
+	var (
+		userPages  int
+		userCursor string
+	)
+	if r.opts.useUserConversations() {
+		fetch := r.refreshUserConversations
+		if skipPublic {
+			fetch = func(ctx context.Context) ([]slack.Channel, string, error) {
+				userPages, userCursor = 0, ""
+				return r.paginate(ctx, sourceUserConversations, countPages(r.fetchUserConversationsPage, &userPages, &userCursor))
+			}
+		}
+		tasks = append(tasks, refreshTask{
+			source: sourceUserConversations,
+			fetch:  fetch,
+		})
+	}
+	if r.opts.usePublicChannels() {
+		task := refreshTask{
+			source:   sourcePublicChannels,
+			fetch:    r.refreshPublicChannels,
+			tolerate: r.tolerateMissingPublicScope,
+		}
+		if skipPublic {
+			task.skip = func(done []refreshResult) bool {
+				// a short page may still carry a cursor, so only a pass of a single page without a next cursor is complete.
+				return userPages == 1 && userCursor == "" && len(done[0].channels) < r.opts.batchSize
+			}
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks
+}
+
+// countPages wraps fetch to count the pages fetched successfully into pages, and record the next cursor of the last page into cursor.
+func countPages(fetch fetchPageFunc, pages *int, cursor *string) fetchPageFunc {
+	return func(ctx context.Context, c string) ([]slack.Channel, string, error) {
+		channels, next, err := fetch(ctx, c)
+		if err == nil {
+			*pages++
+			*cursor = next
+		}
+		return channels, next, err
+	}
+}
+
+// runRefreshTasks runs the tasks with at most refreshWorkers at once, and returns their results in the task order.
+// it returns the first error that is not tolerated, and cancels the remaining tasks.
+func (r *Resolver) runRefreshTasks(ctx context.Context, tasks []refreshTask) ([]refreshResult, error) {
+	results := make([]refreshResult, len(tasks))
+	run := func(ctx context.Context, i int) error {
+		channels, cursor, err := tasks[i].fetch(ctx)
+		if err != nil {
+			if tasks[i].tolerate == nil || !tasks[i].tolerate(err) {
+				return err
+			}
+			results[i].err = err
+			return nil
+		}
+		results[i].channels = channels
+		results[i].cursor = cursor
+		return nil
+	}
+	if r.opts.refreshWorkers <= 1 || len(tasks) <= 1 {
+		for i := range tasks {
+			// a cancellation arriving as a pass completes must not start the next pass.
+			if err := ctx.Err(); err != nil {
+				return nil, &RefreshCanceledError{Source: Source(tasks[i].source), Err: err}
+			}
+			if tasks[i].skip != nil && tasks[i].skip(results[:i]) {
+				r.logger(ctx).DebugContext(ctx, "refresh pass skipped as redundant", "source", tasks[i].source)
+				continue
+			}
+			if err := run(ctx, i); err != nil {
+				return nil, err
+			}
+		}
+		return results, nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	sem := make(chan struct{}, r.opts.refreshWorkers)
+	started := 0
+	for i := range tasks {
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		started++
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := run(ctx, i); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(i)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		if started < len(tasks) {
+			return nil, &RefreshCanceledError{Source: Source(tasks[started].source), Err: err}
+		}
+		return nil, err
+	}
+	return results, nil
+}
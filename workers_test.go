@@ -0,0 +1,122 @@
+package slackcnr_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolverRefresh__RefreshWorkers(t *testing.T) {
+	client := &mockAdminClient{mockSlackClient{t: t}}
+	defer client.AssertExpectations(t)
+	// both teams must be in flight at once to pass the barrier.
+	var barrier sync.WaitGroup
+	barrier.Add(2)
+	wait := func(mock.Arguments) {
+		barrier.Done()
+		barrier.Wait()
+	}
+	client.On("AdminConversationsSearchContext", mock.Anything, &slackcnr.AdminConversationsSearchParameters{
+		Limit:   20,
+		TeamIDs: []string{"T1"},
+	}).Run(wait).Return([]slack.Channel{newTestChannel("C1", "team1-general")}, "cursor1", nil).Once()
+	client.On("AdminConversationsSearchContext", mock.Anything, &slackcnr.AdminConversationsSearchParameters{
+		Cursor:  "cursor1",
+		Limit:   20,
+		TeamIDs: []string{"T1"},
+	}).Return([]slack.Channel{newTestChannel("C2", "team1-random")}, "", nil).Once()
+	client.On("AdminConversationsSearchContext", mock.Anything, &slackcnr.AdminConversationsSearchParameters{
+		Limit:   20,
+		TeamIDs: []string{"T2"},
+	}).Run(wait).Return([]slack.Channel{newTestChannel("C3", "team2-general")}, "", nil).Once()
+	storage := slackcnr.NewInMemoryStorage(0)
+	r, err := slackcnr.NewChecked(client,
+		slackcnr.WithCacheStorage(storage),
+		slackcnr.WithAdminEnumeration("T1", "T2"),
+		slackcnr.WithRefreshWorkers(2),
+	)
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	require.NoError(t, r.Refresh(ctx))
+
+	channels, err := storage.List(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"team1-general", "team1-random", "team2-general"}, channelNames(channels))
+	require.EqualValues(t, 3, r.Stats().CachedChannels)
+}
+
+func TestResolverRefresh__RefreshWorkersError(t *testing.T) {
+	client := &mockSlackClient{t: t}
+	defer client.AssertExpectations(t)
+	userStarted := make(chan struct{})
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		close(userStarted)
+		<-args.Get(0).(context.Context).Done()
+	}).Return([]slack.Channel{}, "", context.Canceled).Once()
+	client.On("GetConversationsContext", mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+		<-userStarted
+	}).Return([]slack.Channel{}, "", errors.New("boom")).Once()
+	r := slackcnr.New(client,
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+		slackcnr.WithSearchPublicChannels(),
+		slackcnr.WithRefreshWorkers(2),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	// the failing pass cancels the other, and its error is returned rather than the cancellation.
+	require.EqualError(t, r.Refresh(ctx), "boom")
+}
+
+func TestResolverRefresh__SkipPublicIfUserComplete(t *testing.T) {
+	cases := []struct {
+		name         string
+		userChannels int
+		shortPages   bool
+		skipped      bool
+	}{
+		{name: "single page", userChannels: 2, skipped: true},
+		{name: "full page", userChannels: 3, skipped: false},
+		{name: "short pages with a cursor", userChannels: 2, shortPages: true, skipped: false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client := &mockSlackClient{t: t}
+			defer client.AssertExpectations(t)
+			var channels []slack.Channel
+			for i := 0; i < c.userChannels; i++ {
+				channel := newTestChannel(fmt.Sprintf("C%09d", i), fmt.Sprintf("channel-%d", i))
+				channel.IsMember = true
+				channels = append(channels, channel)
+			}
+			if c.shortPages {
+				client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return(channels[:1], "next", nil).Once()
+				client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return(channels[1:], "", nil).Once()
+			} else {
+				client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return(channels, "", nil).Once()
+			}
+			if !c.skipped {
+				client.On("GetConversationsContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+					newTestChannel("C999999999", "not-joined"),
+				}, "", nil).Once()
+			}
+			r := slackcnr.New(client,
+				slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+				slackcnr.WithSearchPublicChannels(),
+				slackcnr.WithBatchSize(3),
+				slackcnr.WithSkipPublicIfUserComplete(),
+			)
+			require.NoError(t, r.Refresh(context.Background()))
+			_, err := r.Lookup(context.Background(), "channel-0")
+			require.NoError(t, err)
+		})
+	}
+}
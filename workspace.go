@@ -0,0 +1,81 @@
+package slackcnr
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// AuthTestClient is an optional interface for slack clients that support auth.test API.
+// *slack.Client implements it.
+type AuthTestClient interface {
+	AuthTestContext(ctx context.Context) (*slack.AuthTestResponse, error)
+}
+
+// ResettableStorage is an optional interface for storages that can drop all cached channels.
+type ResettableStorage interface {
+	Storage
+	// Reset removes all channels and the sync state, so that the storage needs a refresh.
+	Reset(ctx context.Context) error
+}
+
+// ErrAuthTestNotSupported is returned by NewChecked when WithWorkspaceGuard is set and the slack client does not implement AuthTestClient.
+var ErrAuthTestNotSupported = errors.New("slack client does not support auth.test")
+
+// WithWorkspaceGuard checks the team ID of the token with auth.test API at each refresh,
+// and resets the cache storage if it changed since the last refresh, such as after a token rotation to another workspace.
+// it costs one extra API call per refresh, counted by WithMaxAPICallsPerRefresh.
+// it requires the slack client to implement AuthTestClient and the cache storage to implement ResettableStorage,
+// NewChecked returns an error otherwise.
+func WithWorkspaceGuard() ResolverOption {
+	return func(o *resolverOptions) {
+		o.workspaceGuard = true
+	}
+}
+
+// guardWorkspace resets the cache if the team of the token changed since the last refresh. it must be called with mu held.
+func (r *Resolver) guardWorkspace(ctx context.Context) error {
+	client, ok := r.client.(AuthTestClient)
+	if !ok {
+		return nil
+	}
+	if err := r.countAPICall(); err != nil {
+		return err
+	}
+	callCtx, cancel := r.callContext(ctx)
+	defer cancel()
+	resp, err := client.AuthTestContext(callCtx)
+	if err != nil {
+		return err
+	}
+	if r.teamID != "" && r.teamID != resp.TeamID {
+		r.logger(ctx).WarnContext(ctx, "workspace of the token changed, resetting the cache", "previous_team_id", r.teamID, "team_id", resp.TeamID)
+		if s, ok := r.cacheStorage().(ResettableStorage); ok {
+			if err := s.Reset(ctx); err != nil {
+				return err
+			}
+		}
+		r.changes.reset()
+		r.renames.reset()
+	}
+	r.teamID = resp.TeamID
+	return nil
+}
+
+func (s *InMemoryStorage) Reset(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.channels = make(map[string]slack.Channel)
+	s.namesById = make(map[string]string)
+	s.keysById = make(map[string][]string)
+	s.idsByName = make(map[string][]string)
+	if s.ages != nil {
+		s.ages = make(map[string]*entryAge)
+	}
+	s.lastSetTime = time.Time{}
+	s.syncState = SyncState{}
+	return nil
+}
@@ -0,0 +1,65 @@
+package slackcnr_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mashiike/slackcnr"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAuthTestClient struct {
+	mockSlackClient
+}
+
+func (m *mockAuthTestClient) AuthTestContext(ctx context.Context) (*slack.AuthTestResponse, error) {
+	args := m.Called(ctx)
+	resp, ok := args.Get(0).(*slack.AuthTestResponse)
+	if resp != nil && !ok {
+		m.t.Error("failed to cast auth test response")
+	}
+	return resp, args.Error(1)
+}
+
+func TestResolverRefresh__WorkspaceGuard(t *testing.T) {
+	client := &mockAuthTestClient{mockSlackClient{t: t}}
+	defer client.AssertExpectations(t)
+	client.On("AuthTestContext", mock.Anything).Return(&slack.AuthTestResponse{TeamID: "T1"}, nil).Twice()
+	client.On("AuthTestContext", mock.Anything).Return(&slack.AuthTestResponse{TeamID: "T2"}, nil).Once()
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C1", "team1-only"),
+	}, "", nil).Twice()
+	client.On("GetConversationsForUserContext", mock.Anything, mock.Anything).Return([]slack.Channel{
+		newTestChannel("C2", "team2-only"),
+	}, "", nil).Once()
+	r, err := slackcnr.NewChecked(client,
+		slackcnr.WithCacheStorage(slackcnr.NewInMemoryStorage(0)),
+		slackcnr.WithWorkspaceGuard(),
+	)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, r.Refresh(ctx))
+	require.NoError(t, r.Refresh(ctx))
+	_, err = r.Lookup(ctx, "team1-only")
+	require.NoError(t, err, "the cache is kept while the team is unchanged")
+
+	require.NoError(t, r.Refresh(ctx))
+	_, err = r.Lookup(ctx, "team1-only")
+	require.ErrorIs(t, err, slackcnr.ErrNotFound, "the cache of the previous team is reset")
+	channel, err := r.Lookup(ctx, "team2-only")
+	require.NoError(t, err)
+	require.Equal(t, "C2", channel.ID)
+}
+
+func TestNewChecked__WorkspaceGuardNotSupported(t *testing.T) {
+	_, err := slackcnr.NewChecked(&mockSlackClient{t: t}, slackcnr.WithWorkspaceGuard())
+	require.ErrorIs(t, err, slackcnr.ErrAuthTestNotSupported)
+	_, err = slackcnr.NewChecked(&mockAuthTestClient{mockSlackClient{t: t}},
+		slackcnr.WithCacheStorage(slackcnr.NewLRUStorage(10, 0)),
+		slackcnr.WithWorkspaceGuard(),
+	)
+	require.Error(t, err)
+}